@@ -0,0 +1,239 @@
+package benchmarks_test
+
+import (
+	"container/list"
+	"datatypes/linkedlist"
+	"datatypes/queue"
+	"datatypes/stack"
+	"sync"
+	"testing"
+)
+
+//Number of values kept in the containers while benchmarking the steady state.
+const STEADY_STATE_LENGTH = 1000
+
+//*************** FIFO Comparison ***************
+
+func BenchmarkFIFO(b *testing.B) {
+	b.Run("queue.Queue", func(b *testing.B) {
+		aQueue := queue.NewQueue()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aQueue.Enqueue(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aQueue.Enqueue(i)
+			aQueue.Dequeue()
+		}
+	})
+
+	b.Run("container/list", func(b *testing.B) {
+		aList := list.New()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aList.PushBack(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aList.PushBack(i)
+			aList.Remove(aList.Front())
+		}
+	})
+
+	b.Run("channel", func(b *testing.B) {
+		aChannel := make(chan interface{}, STEADY_STATE_LENGTH+1)
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aChannel <- i
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aChannel <- i
+			<-aChannel
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		aSlice := make([]interface{}, 0, STEADY_STATE_LENGTH)
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aSlice = append(aSlice, i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aSlice = append(aSlice, i)
+			aSlice = aSlice[1:]
+		}
+	})
+}
+
+//BenchmarkParallelFIFO compares the containers under contention. The slice is guarded by a mutex.
+func BenchmarkParallelFIFO(b *testing.B) {
+	b.Run("queue.Queue", func(b *testing.B) {
+		aQueue := queue.NewQueue()
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				aQueue.Enqueue(0)
+				aQueue.Dequeue()
+			}
+		})
+	})
+
+	b.Run("channel", func(b *testing.B) {
+		aChannel := make(chan interface{}, 1024)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				aChannel <- 0
+				<-aChannel
+			}
+		})
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		var mutex sync.Mutex
+		aSlice := []interface{}{}
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				mutex.Lock()
+				aSlice = append(aSlice, 0)
+				mutex.Unlock()
+				mutex.Lock()
+				aSlice = aSlice[1:]
+				mutex.Unlock()
+			}
+		})
+	})
+}
+
+//*************** LIFO Comparison ***************
+
+func BenchmarkLIFO(b *testing.B) {
+	b.Run("stack.Stack", func(b *testing.B) {
+		aStack := stack.NewStack()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aStack.Push(i)
+			aStack.Pop()
+		}
+	})
+
+	b.Run("container/list", func(b *testing.B) {
+		aList := list.New()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aList.PushBack(i)
+			aList.Remove(aList.Back())
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		aSlice := []interface{}{}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aSlice = append(aSlice, i)
+			aSlice = aSlice[:len(aSlice)-1]
+		}
+	})
+}
+
+//*************** List Comparison ***************
+
+func BenchmarkListAppend(b *testing.B) {
+	b.Run("linkedlist.LinkedList", func(b *testing.B) {
+		linkedL := linkedlist.NewLinkedList()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			linkedL.Append(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			linkedL.Append(i)
+			linkedL.Remove(STEADY_STATE_LENGTH)
+		}
+	})
+
+	b.Run("container/list", func(b *testing.B) {
+		aList := list.New()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aList.PushBack(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aList.PushBack(i)
+			aList.Remove(aList.Back())
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		aSlice := make([]interface{}, 0, STEADY_STATE_LENGTH+1)
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aSlice = append(aSlice, i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aSlice = append(aSlice, i)
+			aSlice = aSlice[:STEADY_STATE_LENGTH]
+		}
+	})
+}
+
+func BenchmarkListGetMiddle(b *testing.B) {
+	b.Run("linkedlist.LinkedList", func(b *testing.B) {
+		linkedL := linkedlist.NewLinkedList()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			linkedL.Append(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			linkedL.GetValue(STEADY_STATE_LENGTH / 2)
+		}
+	})
+
+	b.Run("container/list", func(b *testing.B) {
+		aList := list.New()
+		for i := 0; i < STEADY_STATE_LENGTH; i++ {
+			aList.PushBack(i)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			element := aList.Front()
+			for j := 0; j < STEADY_STATE_LENGTH/2; j++ {
+				element = element.Next()
+			}
+			_ = element.Value
+		}
+	})
+
+	b.Run("slice", func(b *testing.B) {
+		aSlice := make([]interface{}, STEADY_STATE_LENGTH)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = aSlice[STEADY_STATE_LENGTH/2]
+		}
+	})
+}
+
+//Sanity check that the benchmark setup itself is correct.
+func TestSteadyStateSetup(t *testing.T) {
+	aQueue := queue.NewQueue()
+	for i := 0; i < STEADY_STATE_LENGTH; i++ {
+		aQueue.Enqueue(i)
+	}
+	aQueue.Enqueue(STEADY_STATE_LENGTH)
+	value, err := aQueue.Dequeue()
+	if err != nil || value != 0 || aQueue.Length() != STEADY_STATE_LENGTH {
+		t.Errorf("Unexpected queue state, value %v, length %d", value, aQueue.Length())
+	}
+}
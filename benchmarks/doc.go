@@ -0,0 +1,6 @@
+//Benchmarks compares the containers in this repository with the standard library alternatives.
+//Contains no code, run `go test -bench . -benchmem` in this directory.
+//Queue is compared with container/list, a buffered channel and a slice.
+//Stack is compared with container/list and a slice.
+//LinkedList is compared with container/list and a slice.
+package benchmarks
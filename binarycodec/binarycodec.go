@@ -0,0 +1,174 @@
+//Binarycodec implements the binary format shared by the containers in this repository.
+//An encoded container starts with a versioned header, followed by a length prefix and the encoded elements.
+//Every element is encoded by a pluggable Codec and stored with its own length prefix.
+//Containers use it to implement encoding.BinaryMarshaler, encoding.BinaryUnmarshaler, gob.GobEncoder and gob.GobDecoder.
+package binarycodec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+//*************** Binary Codec Public Interface ***************
+
+//Current version of the format. Written into every header.
+const Version = 1
+
+//Container kinds stored in the header, so a queue can't be decoded as a stack by accident.
+const (
+	KindQueue      byte = 'Q'
+	KindStack      byte = 'S'
+	KindLinkedList byte = 'L'
+)
+
+//Codec encodes and decodes single container elements.
+type Codec interface {
+	EncodeElement(value interface{}) ([]byte, error)
+	DecodeElement(data []byte) (interface{}, error)
+}
+
+//GobCodec encodes elements with encoding/gob. Default codec of all containers.
+//Values of non-basic types have to be registered with gob.Register.
+type GobCodec struct{}
+
+//EncodeElement encodes a value as a gob interface value.
+func (GobCodec) EncodeElement(value interface{}) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+//DecodeElement decodes a value encoded by EncodeElement.
+func (GobCodec) DecodeElement(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+//Encode writes the header, the number of values and every value encoded by the codec.
+func Encode(kind byte, values []interface{}, codec Codec) ([]byte, error) {
+	if codec == nil {
+		return nil, errors.New("Codec is nil")
+	}
+
+	buffer := bytes.Buffer{}
+	buffer.Write(magic)
+	buffer.WriteByte(kind)
+	buffer.WriteByte(Version)
+	writeUvarint(&buffer, uint64(len(values)))
+
+	for i, value := range values {
+		encodedValue, err := codec.EncodeElement(value)
+		if err != nil {
+			return nil, fmt.Errorf("Can't encode element %d - %s", i, err.Error())
+		}
+		writeUvarint(&buffer, uint64(len(encodedValue)))
+		buffer.Write(encodedValue)
+	}
+	return buffer.Bytes(), nil
+}
+
+//Decode checks the header and returns all values decoded by the codec, in the order they were encoded.
+func Decode(kind byte, data []byte, codec Codec) ([]interface{}, error) {
+	if codec == nil {
+		return nil, errors.New("Codec is nil")
+	}
+
+	reader := bytes.NewReader(data)
+	header := make([]byte, len(magic)+2)
+	if _, err := io.ReadFull(reader, header); err != nil || !bytes.Equal(header[:len(magic)], magic) {
+		return nil, errors.New("Can't decode - header is missing")
+	}
+	if header[len(magic)] != kind {
+		return nil, fmt.Errorf("Can't decode - encoded container kind is %q, expected %q", header[len(magic)], kind)
+	}
+	if header[len(magic)+1] != Version {
+		return nil, fmt.Errorf("Can't decode - unsupported version %d", header[len(magic)+1])
+	}
+
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, errors.New("Can't decode - length prefix is missing")
+	}
+	//Every element takes at least one byte, so a larger count means corrupted data
+	if count > uint64(reader.Len()) {
+		return nil, errors.New("Can't decode - length prefix exceeds data length")
+	}
+
+	values := make([]interface{}, 0, int(count))
+	for i := 0; i < int(count); i++ {
+		size, err := binary.ReadUvarint(reader)
+		if err != nil || size > uint64(reader.Len()) {
+			return nil, fmt.Errorf("Can't decode element %d - data is truncated", i)
+		}
+		encodedValue := make([]byte, int(size))
+		io.ReadFull(reader, encodedValue)
+
+		value, err := codec.DecodeElement(encodedValue)
+		if err != nil {
+			return nil, fmt.Errorf("Can't decode element %d - %s", i, err.Error())
+		}
+		values = append(values, value)
+	}
+
+	if reader.Len() != 0 {
+		return nil, errors.New("Can't decode - unexpected data after the last element")
+	}
+	return values, nil
+}
+
+//WriteFile writes data to a temporary file next to path, flushes it and renames it over path.
+//A crash leaves either the old or the new file in place, never a partially written one.
+func WriteFile(path string, data []byte) error {
+	temporaryFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	temporaryPath := temporaryFile.Name()
+
+	if _, err := temporaryFile.Write(data); err != nil {
+		temporaryFile.Close()
+		os.Remove(temporaryPath)
+		return err
+	}
+	if err := temporaryFile.Sync(); err != nil {
+		temporaryFile.Close()
+		os.Remove(temporaryPath)
+		return err
+	}
+	if err := temporaryFile.Close(); err != nil {
+		os.Remove(temporaryPath)
+		return err
+	}
+	if err := os.Rename(temporaryPath, path); err != nil {
+		os.Remove(temporaryPath)
+		return err
+	}
+
+	//Make the rename durable. Best effort, not supported on every platform.
+	if directory, err := os.Open(filepath.Dir(path)); err == nil {
+		directory.Sync()
+		directory.Close()
+	}
+	return nil
+}
+
+//*************** Binary Codec Internal Structure ***************
+
+var magic = []byte("DT")
+
+func writeUvarint(buffer *bytes.Buffer, value uint64) {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	size := binary.PutUvarint(encoded, value)
+	buffer.Write(encoded[:size])
+}
@@ -0,0 +1,144 @@
+package binarycodec_test
+
+import (
+	. "datatypes/binarycodec"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//stringCodec stores strings as raw bytes and rejects everything else.
+type stringCodec struct{}
+
+func (stringCodec) EncodeElement(value interface{}) ([]byte, error) {
+	aString, ok := value.(string)
+	if !ok {
+		return nil, errors.New("Not a string")
+	}
+	return []byte(aString), nil
+}
+
+func (stringCodec) DecodeElement(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+//*************** Public Interface Test ***************
+
+func TestEncode(t *testing.T) {
+	cases := []struct {
+		values       []interface{}
+		codec        Codec
+		expectedData string
+		expectError  bool
+	}{
+		{values: []interface{}{}, codec: stringCodec{}, expectedData: "DTQ\x01\x00", expectError: false},
+		{values: []interface{}{"ab", ""}, codec: stringCodec{}, expectedData: "DTQ\x01\x02\x02ab\x00", expectError: false},
+		{values: []interface{}{1}, codec: stringCodec{}, expectedData: "", expectError: true},
+		{values: []interface{}{}, codec: nil, expectedData: "", expectError: true},
+	}
+
+	for i, aCase := range cases {
+		data, err := Encode(KindQueue, aCase.values, aCase.codec)
+		if aCase.expectError {
+			if err == nil {
+				t.Errorf("Error in case %d. Expected an error, got no error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if string(data) != aCase.expectedData {
+			t.Errorf("Error in case %d. Expected data %q, got %q", i, aCase.expectedData, string(data))
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	cases := []struct {
+		kind           byte
+		data           string
+		expectedValues []interface{}
+		expectError    bool
+	}{
+		{kind: KindStack, data: "DTS\x01\x02\x02ab\x00", expectedValues: []interface{}{"ab", ""}, expectError: false},
+		//Missing or corrupted header
+		{kind: KindStack, data: "", expectError: true},
+		{kind: KindStack, data: "XXS\x01\x00", expectError: true},
+		//Wrong container kind
+		{kind: KindStack, data: "DTQ\x01\x00", expectError: true},
+		//Unsupported version
+		{kind: KindStack, data: "DTS\x02\x00", expectError: true},
+		//Length prefix larger than the data
+		{kind: KindStack, data: "DTS\x01\x05\x00", expectError: true},
+		//Truncated element
+		{kind: KindStack, data: "DTS\x01\x01\x05ab", expectError: true},
+		//Trailing data
+		{kind: KindStack, data: "DTS\x01\x01\x01a\x00", expectError: true},
+	}
+
+	for i, aCase := range cases {
+		values, err := Decode(aCase.kind, []byte(aCase.data), stringCodec{})
+		if aCase.expectError {
+			if err == nil {
+				t.Errorf("Error in case %d. Expected an error, got no error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+			continue
+		}
+		if fmt.Sprint(values) != fmt.Sprint(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected values %v, got %v", i, aCase.expectedValues, values)
+		}
+	}
+}
+
+func TestGobCodec(t *testing.T) {
+	values := []interface{}{1, "two", 3.5, []byte("four"), nil}
+	data, err := Encode(KindLinkedList, values, GobCodec{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	decodedValues, err := Decode(KindLinkedList, data, GobCodec{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if fmt.Sprint(decodedValues) != fmt.Sprint(values) {
+		t.Errorf("Expected values %v, got %v", values, decodedValues)
+	}
+
+	//Unregistered types can't be encoded
+	_, err = Encode(KindLinkedList, []interface{}{struct{ A int }{1}}, GobCodec{})
+	if err == nil {
+		t.Errorf("Expected an error for an unregistered type, got no error")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+
+	for _, content := range []string{"first", "second"} {
+		if err := WriteFile(path, []byte(content)); err != nil {
+			t.Fatalf("Expected no error, got %s", err.Error())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != content {
+			t.Errorf("Expected file content %s, got %s (error: %v)", content, string(data), err)
+		}
+	}
+
+	//No temporary files are left behind
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	if len(files) != 1 {
+		t.Errorf("Expected a single file, got %v", files)
+	}
+
+	if err := WriteFile(filepath.Join(path, "not a directory", "file"), nil); err == nil {
+		t.Errorf("Expected an error writing into a missing directory, got no error")
+	}
+}
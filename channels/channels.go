@@ -0,0 +1,158 @@
+//Channels connects the containers in this repository with Go channels.
+//Unbounded exposes a queue.Queue as a pair of channels with an unlimited buffer between them.
+//DrainToQueue and DrainToStack move values from a channel into a container.
+//Safe to use concurrently.
+package channels
+
+import (
+	"context"
+	"datatypes/queue"
+	"datatypes/stack"
+	"errors"
+	"sync"
+)
+
+//*************** Unbounded Channel Public Interface ***************
+
+//Unbounded is a channel pair backed by a queue.Queue. Sends to In never block for long while the pump runs,
+//values are buffered in the queue until they are received from Out. Goroutine safe.
+type Unbounded struct {
+	in        chan interface{}
+	out       chan interface{}
+	buffer    *queue.Queue
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+//NewUnbounded starts the pump goroutine moving values from In, through the queue, to Out.
+//The goroutine stops when the context ends, dropping buffered values, or after Close once every buffered value was received.
+//Nothing reads In after the goroutine stopped, so senders that may outlive the context have to select on Done, or use Send.
+func NewUnbounded(ctx context.Context) *Unbounded {
+	u := &Unbounded{
+		in:     make(chan interface{}),
+		out:    make(chan interface{}),
+		buffer: queue.NewQueue(),
+		done:   make(chan struct{}),
+	}
+	go u.pump(ctx)
+	return u
+}
+
+//In returns the channel accepting values. Sending after Close panics, like on any closed channel.
+//A plain send blocks forever once the context ended, select on Done too or use Send.
+func (u *Unbounded) In() chan<- interface{} {
+	return u.in
+}
+
+//Send sends value to In. Returns an error instead of blocking if the pump stopped because the context ended.
+//Sending after Close panics, like on any closed channel.
+func (u *Unbounded) Send(value interface{}) error {
+	select {
+	case u.in <- value:
+		return nil
+	case <-u.done:
+		return errors.New("Unbounded channel is stopped")
+	}
+}
+
+//Out returns the channel delivering values in the order they were sent.
+//Out is closed after Close once the buffer is empty, or right away when the context ends.
+func (u *Unbounded) Out() <-chan interface{} {
+	return u.out
+}
+
+//Length returns the number of buffered values.
+func (u *Unbounded) Length() int {
+	return u.buffer.Length()
+}
+
+//Close stops accepting values. Buffered values are still delivered through Out. Safe to call more than once.
+func (u *Unbounded) Close() {
+	u.closeOnce.Do(func() {
+		close(u.in)
+	})
+}
+
+//Done returns a channel closed once the pump goroutine has exited. Nothing is received from In or sent to Out after that.
+func (u *Unbounded) Done() <-chan struct{} {
+	return u.done
+}
+
+//*************** Draining Channels ***************
+
+//DrainToQueue enqueues every value received from the channel until the channel is closed or the context ends.
+//Returns nil once the channel is closed, the context error otherwise.
+func DrainToQueue(ctx context.Context, values <-chan interface{}, q *queue.Queue) error {
+	return drain(ctx, values, func(value interface{}) error {
+		q.Enqueue(value)
+		return nil
+	})
+}
+
+//DrainToStack pushes every value received from the channel until the channel is closed or the context ends.
+//Returns nil once the channel is closed, the context error otherwise.
+//On a bounded stack the push error is returned, after which the channel is no longer read.
+func DrainToStack(ctx context.Context, values <-chan interface{}, s *stack.Stack) error {
+	return drain(ctx, values, func(value interface{}) error {
+		return s.PushContext(ctx, value)
+	})
+}
+
+//*************** Channels Internal Structure ***************
+
+func (u *Unbounded) pump(ctx context.Context) {
+	defer close(u.done)
+	defer close(u.out)
+
+	in := u.in
+	for {
+		front, err := u.buffer.Peek()
+		if err != nil {
+			//Buffer is empty - only receiving makes sense
+			if in == nil {
+				return
+			}
+			select {
+			case value, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				u.buffer.Enqueue(value)
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		//A nil in channel blocks forever, so after Close only sending is possible
+		select {
+		case value, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			u.buffer.Enqueue(value)
+		case u.out <- front:
+			u.buffer.Dequeue()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func drain(ctx context.Context, values <-chan interface{}, add func(value interface{}) error) error {
+	for {
+		select {
+		case value, ok := <-values:
+			if !ok {
+				return nil
+			}
+			if err := add(value); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package channels_test
+
+import (
+	"context"
+	. "datatypes/channels"
+	"datatypes/queue"
+	"datatypes/stack"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+//waitForGoroutines fails the test if the number of goroutines doesn't drop back to the expected count.
+func waitForGoroutines(t *testing.T, expected int) {
+	for i := 0; i < 100; i++ {
+		if runtime.NumGoroutine() <= expected {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("Goroutine leak. Expected at most %d goroutines, got %d", expected, runtime.NumGoroutine())
+}
+
+//*************** Unbounded Channel Test ***************
+
+func TestUnbounded(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	u := NewUnbounded(context.Background())
+
+	//Sends don't wait for a receiver
+	for i := 0; i < 1000; i++ {
+		u.In() <- i
+	}
+	u.Close()
+	u.Close()
+
+	received := 0
+	for value := range u.Out() {
+		if value != received {
+			t.Fatalf("Expected value %d, got %v", received, value)
+		}
+		received++
+	}
+	if received != 1000 {
+		t.Errorf("Expected 1000 values, got %d", received)
+	}
+
+	<-u.Done()
+	waitForGoroutines(t, goroutines)
+}
+
+func TestUnboundedCancel(t *testing.T) {
+	goroutines := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnbounded(ctx)
+
+	u.In() <- "buffered"
+	//Wait for the value to reach the buffer
+	for u.Length() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-u.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Pump goroutine didn't stop after the context was cancelled")
+	}
+	//Out is closed, buffered values are dropped
+	for range u.Out() {
+	}
+
+	//The pump is gone without Close
+	waitForGoroutines(t, goroutines)
+
+	//Sends after the context ended fail instead of blocking
+	if err := u.Send("late"); err == nil {
+		t.Errorf("Expected Send to fail after the context was cancelled")
+	}
+	select {
+	case u.In() <- "late":
+		t.Errorf("Expected nothing to receive from In after the context was cancelled")
+	case <-u.Done():
+	}
+}
+
+//*************** Draining Channels Test ***************
+
+func TestDrainToQueue(t *testing.T) {
+	values := make(chan interface{}, 3)
+	values <- 1
+	values <- 2
+	values <- 3
+	close(values)
+
+	aQueue := queue.NewQueue()
+	if err := DrainToQueue(context.Background(), values, aQueue); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := aQueue.Dequeue(); value != 1 || aQueue.Length() != 2 {
+		t.Errorf("Unexpected queue content, front value %v, length %d", value, aQueue.Length()+1)
+	}
+}
+
+func TestDrainToStack(t *testing.T) {
+	values := make(chan interface{}, 2)
+	values <- "bottom"
+	values <- "top"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	aStack := stack.NewStack()
+	//The channel is never closed, so draining stops when the context ends
+	if err := DrainToStack(ctx, values, aStack); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if value, _ := aStack.Pop(); value != "top" {
+		t.Errorf("Expected value top, got %v", value)
+	}
+}
+
+func TestDrainToFullStack(t *testing.T) {
+	values := make(chan interface{}, 2)
+	values <- "fits"
+	values <- "waits for space"
+
+	aStack := stack.NewStack()
+	aStack.SetCapacity(1, stack.OverflowBlock)
+	ctx, cancel := context.WithCancel(context.Background())
+	drained := make(chan error)
+	go func() {
+		drained <- DrainToStack(ctx, values, aStack)
+	}()
+
+	//Wait for the first value, the second push then blocks on the full stack
+	for aStack.Length() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case err := <-drained:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Drain blocked on the full stack after the context was cancelled")
+	}
+	if top, _ := aStack.Peek(); top != "fits" || aStack.Length() != 1 {
+		t.Errorf("Expected only the first value pushed, got %v", top)
+	}
+}
+
+func Example() {
+	u := NewUnbounded(context.Background())
+	u.In() <- "first"
+	u.In() <- "second"
+	u.Close()
+
+	for value := range u.Out() {
+		fmt.Println(value)
+	}
+	//Output:
+	//first
+	//second
+}
+
+//*************** Concurrency Test ***************
+
+//TestConcurrency sends and receives from multiple goroutines. Run with `go test -race` for better race detection.
+func TestConcurrency(t *testing.T) {
+	u := NewUnbounded(context.Background())
+	var senders sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for j := 0; j < 100; j++ {
+				u.In() <- j
+			}
+		}()
+	}
+
+	received := make(chan int)
+	for i := 0; i < 4; i++ {
+		go func() {
+			count := 0
+			for range u.Out() {
+				count++
+			}
+			received <- count
+		}()
+	}
+
+	senders.Wait()
+	u.Close()
+	total := 0
+	for i := 0; i < 4; i++ {
+		total += <-received
+	}
+	if total != 1000 {
+		t.Errorf("Expected 1000 values, got %d", total)
+	}
+}
@@ -0,0 +1,227 @@
+//Dedupqueue is an implementation of a FIFO queue of keys where every key is queued at most once.
+//Follows the dirty/processing semantics of the Kubernetes workqueue:
+//a key added while it is queued is coalesced, a key added while it is being processed
+//is queued again once processing is done, so the same key is never processed twice at the same time.
+//Every key carries a payload, duplicates either keep the first payload or replace it in place.
+//Safe to use concurrently.
+package dedupqueue
+
+import (
+	"context"
+	"datatypes/queue"
+	"errors"
+	"sync"
+)
+
+//*************** Dedup Queue Public Interface ***************
+
+//Duplicates selects what happens to the payload when a queued key is enqueued again.
+type Duplicates int
+
+const (
+	//Keep the payload of the first enqueue.
+	KeepFirst Duplicates = iota
+	//Replace the payload, the key keeps its position.
+	ReplacePayload
+)
+
+//Options configures a DedupQueue. The zero value keeps the first payload.
+type Options struct {
+	Duplicates Duplicates
+}
+
+//DedupQueue is a FIFO queue of unique keys with payloads. Keys have to be comparable. Goroutine safe.
+type DedupQueue struct {
+	options Options
+	//Keys in FIFO order. A key is in here only if it is dirty and not processing.
+	keys *queue.Queue
+	//Keys waiting to be processed, with their payloads
+	dirty map[interface{}]interface{}
+	//Keys handed out by Dequeue and not yet marked done
+	processing map[interface{}]struct{}
+	//Closed and replaced whenever a key is queued, wakes up waiting consumers
+	changed chan struct{}
+	mutex   sync.Mutex
+}
+
+//NewDedupQueue initializes an empty DedupQueue. Recommended way of initialization.
+func NewDedupQueue(options Options) *DedupQueue {
+	return &DedupQueue{
+		options:    options,
+		keys:       queue.NewQueue(),
+		dirty:      map[interface{}]interface{}{},
+		processing: map[interface{}]struct{}{},
+		changed:    make(chan struct{}),
+	}
+}
+
+//Length returns the number of keys waiting to be dequeued. Returns 0 on an uninitialized DedupQueue.
+//Keys that are dirty while being processed are not counted until they are done.
+func (dq *DedupQueue) Length() int {
+	if dq == nil {
+		return 0
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	return dq.keys.Length()
+}
+
+//Processing returns the number of keys dequeued and not yet marked done.
+func (dq *DedupQueue) Processing() int {
+	if dq == nil {
+		return 0
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	return len(dq.processing)
+}
+
+//IsQueued reports whether the key is waiting to be processed, including after its current processing is done.
+func (dq *DedupQueue) IsQueued(key interface{}) bool {
+	if dq == nil {
+		return false
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	_, ok := dq.dirty[key]
+	return ok
+}
+
+//IsProcessing reports whether the key was dequeued and not yet marked done.
+func (dq *DedupQueue) IsProcessing(key interface{}) bool {
+	if dq == nil {
+		return false
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	_, ok := dq.processing[key]
+	return ok
+}
+
+//Enqueue adds the key with a payload. Returns false if the key was already queued and got coalesced,
+//in which case the payload is kept or replaced depending on Options.Duplicates.
+//A key being processed is queued again only after Done.
+//Panics on an uninitialized queue.
+func (dq *DedupQueue) Enqueue(key interface{}, payload interface{}) (added bool) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	if _, ok := dq.dirty[key]; ok {
+		if dq.options.Duplicates == ReplacePayload {
+			dq.dirty[key] = payload
+		}
+		return false
+	}
+
+	dq.dirty[key] = payload
+	if _, ok := dq.processing[key]; ok {
+		return true
+	}
+	dq.queueKey(key)
+	return true
+}
+
+//Dequeue removes the key at the front and marks it as processing. Done has to be called once it is processed.
+//If no key is waiting, returns an error.
+//Panics on an uninitialized queue.
+func (dq *DedupQueue) Dequeue() (key interface{}, payload interface{}, err error) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	return dq.dequeueKey()
+}
+
+//DequeueWait removes the key at the front, waiting until one is queued or the context ends.
+//Returns the context error if the context ends first.
+//Panics on an uninitialized queue.
+func (dq *DedupQueue) DequeueWait(ctx context.Context) (key interface{}, payload interface{}, err error) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	for {
+		dq.mutex.Lock()
+		key, payload, err = dq.dequeueKey()
+		changed := dq.changed
+		dq.mutex.Unlock()
+		if err == nil {
+			return key, payload, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+//Done marks a dequeued key as processed. If the key was enqueued again in the meantime, it goes to the back of the queue.
+//Returns an error if the key is not being processed.
+//Panics on an uninitialized queue.
+func (dq *DedupQueue) Done(key interface{}) error {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.mutex.Lock()
+	defer dq.mutex.Unlock()
+
+	if _, ok := dq.processing[key]; !ok {
+		return errors.New("Key is not being processed")
+	}
+	delete(dq.processing, key)
+	if _, ok := dq.dirty[key]; ok {
+		dq.queueKey(key)
+	}
+	return nil
+}
+
+//*************** Dedup Queue Internal Structure ***************
+
+//Make runtime asserts fatal
+const (
+	panic_on_internal_inconsistencies = true
+)
+
+//queueKey puts a dirty key at the back and wakes up waiting consumers. No locking.
+func (dq *DedupQueue) queueKey(key interface{}) {
+	dq.keys.Enqueue(key)
+	close(dq.changed)
+	dq.changed = make(chan struct{})
+}
+
+//dequeueKey moves the key at the front from dirty to processing. No locking.
+func (dq *DedupQueue) dequeueKey() (key interface{}, payload interface{}, err error) {
+	key, err = dq.keys.Dequeue()
+	if err != nil {
+		return nil, nil, errors.New("Queue is empty")
+	}
+
+	payload, ok := dq.dirty[key]
+	if !ok {
+		if panic_on_internal_inconsistencies {
+			panic("Queued key is not dirty")
+		}
+		return nil, nil, errors.New("Queued key is not dirty")
+	}
+	delete(dq.dirty, key)
+	dq.processing[key] = struct{}{}
+	return key, payload, nil
+}
@@ -0,0 +1,157 @@
+package dedupqueue_test
+
+import (
+	"context"
+	. "datatypes/dedupqueue"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//*************** Public Interface Test ***************
+
+func TestCoalescing(t *testing.T) {
+	cases := []struct {
+		duplicates      Duplicates
+		expectedPayload interface{}
+	}{
+		{duplicates: KeepFirst, expectedPayload: "first"},
+		{duplicates: ReplacePayload, expectedPayload: "second"},
+	}
+
+	for i, aCase := range cases {
+		dq := NewDedupQueue(Options{Duplicates: aCase.duplicates})
+		if !dq.Enqueue("pod/a", "first") {
+			t.Errorf("Error in case %d. Expected the first enqueue to add the key", i)
+		}
+		dq.Enqueue("pod/b", "other")
+		if dq.Enqueue("pod/a", "second") {
+			t.Errorf("Error in case %d. Expected the duplicate to be coalesced", i)
+		}
+		if dq.Length() != 2 {
+			t.Errorf("Error in case %d. Expected length 2, got %d", i, dq.Length())
+		}
+
+		key, payload, err := dq.Dequeue()
+		if err != nil || key != "pod/a" || payload != aCase.expectedPayload {
+			t.Errorf("Error in case %d. Expected pod/a with %v, got %v with %v and error %v", i, aCase.expectedPayload, key, payload, err)
+		}
+	}
+}
+
+func TestDirtyWhileProcessing(t *testing.T) {
+	dq := NewDedupQueue(Options{})
+	dq.Enqueue("a", 1)
+	dq.Enqueue("b", 1)
+
+	key, _, _ := dq.Dequeue()
+	if key != "a" || !dq.IsProcessing("a") || dq.IsQueued("a") {
+		t.Fatalf("Expected a to be processing and not queued")
+	}
+
+	//Enqueued while processing - held back until Done
+	if !dq.Enqueue("a", 2) {
+		t.Errorf("Expected the key to be added while it is processing")
+	}
+	dq.Enqueue("a", 3)
+	if dq.Length() != 1 || !dq.IsQueued("a") {
+		t.Errorf("Expected only b to be dequeueable, got length %d", dq.Length())
+	}
+
+	if err := dq.Done("a"); err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	if err := dq.Done("a"); err == nil {
+		t.Errorf("Expected an error when marking a key done twice")
+	}
+	if dq.Length() != 2 || dq.Processing() != 0 {
+		t.Errorf("Expected a to be queued again behind b, got length %d", dq.Length())
+	}
+
+	order := []interface{}{}
+	for dq.Length() > 0 {
+		key, payload, _ := dq.Dequeue()
+		order = append(order, fmt.Sprintf("%v%v", key, payload))
+		dq.Done(key)
+	}
+	if fmt.Sprint(order) != "[b1 a2]" {
+		t.Errorf("Expected [b1 a2], got %v", order)
+	}
+	if _, _, err := dq.Dequeue(); err == nil {
+		t.Errorf("Expected an error on an empty queue")
+	}
+}
+
+func TestDequeueWait(t *testing.T) {
+	dq := NewDedupQueue(Options{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := dq.DequeueWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	dq.Enqueue("a", nil)
+	dq.Dequeue()
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		dq.Enqueue("a", "again")
+		time.Sleep(5 * time.Millisecond)
+		dq.Done("a")
+	}()
+	key, payload, err := dq.DequeueWait(context.Background())
+	if err != nil || key != "a" || payload != "again" {
+		t.Errorf("Expected a to be handed out again after Done, got %v with %v and error %v", key, payload, err)
+	}
+}
+
+func TestNoConcurrentProcessingOfAKey(t *testing.T) {
+	dq := NewDedupQueue(Options{Duplicates: ReplacePayload})
+	active := map[interface{}]*int32{}
+	for i := 0; i < 5; i++ {
+		active[i] = new(int32)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				key, _, err := dq.DequeueWait(ctx)
+				if err != nil {
+					return
+				}
+				if atomic.AddInt32(active[key], 1) != 1 {
+					t.Errorf("Key %v processed by two workers at once", key)
+				}
+				time.Sleep(10 * time.Microsecond)
+				atomic.AddInt32(active[key], -1)
+				dq.Done(key)
+			}
+		}()
+	}
+
+	for i := 0; i < 2000; i++ {
+		dq.Enqueue(i%5, i)
+	}
+	for dq.Length() > 0 || dq.Processing() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	wg.Wait()
+}
+
+func ExampleDedupQueue() {
+	dq := NewDedupQueue(Options{Duplicates: ReplacePayload})
+	dq.Enqueue("default/web", "v1")
+	dq.Enqueue("default/web", "v2")
+
+	key, payload, _ := dq.Dequeue()
+	fmt.Println(key, payload, dq.Length())
+	dq.Done(key)
+	//Output: default/web v2 0
+}
@@ -0,0 +1,215 @@
+//Delayqueue is an implementation of a queue of values that become available at a scheduled time.
+//Values are dequeued in order of their due time, values due at the same time in the order they were enqueued.
+//Values that are not due yet stay hidden.
+//Safe to use concurrently.
+package delayqueue
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//*************** Delay Queue Public Interface ***************
+
+//Clock is the source of time used by a DelayQueue. Can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(duration time.Duration) <-chan time.Time
+}
+
+//DelayQueue is a queue of values ordered by due time. Goroutine safe.
+type DelayQueue struct {
+	clock   Clock
+	items   itemHeap
+	nextSeq uint64
+	//Closed and replaced whenever a value is added, wakes up waiting consumers
+	changed chan struct{}
+	rwMutex sync.RWMutex
+}
+
+//NewDelayQueue initializes an empty DelayQueue using the system clock. Recommended way of initialization.
+func NewDelayQueue() *DelayQueue {
+	return NewDelayQueueWithClock(systemClock{})
+}
+
+//NewDelayQueueWithClock initializes an empty DelayQueue using the given clock.
+func NewDelayQueueWithClock(clock Clock) *DelayQueue {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &DelayQueue{clock: clock, items: itemHeap{}, changed: make(chan struct{})}
+}
+
+//Length returns the number of values in the queue, due or not. Returns 0 on an uninitialized DelayQueue.
+func (dq *DelayQueue) Length() int {
+	if dq == nil {
+		return 0
+	}
+
+	dq.rwMutex.RLock()
+	defer dq.rwMutex.RUnlock()
+
+	return len(dq.items)
+}
+
+//NextDue returns the due time of the value that matures first.
+//If the queue is empty or nil, returns an error.
+func (dq *DelayQueue) NextDue() (time.Time, error) {
+	if dq == nil {
+		return time.Time{}, errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.RLock()
+	defer dq.rwMutex.RUnlock()
+
+	if len(dq.items) == 0 {
+		return time.Time{}, errors.New("Queue is empty")
+	}
+	return dq.items[0].due, nil
+}
+
+//Peek returns the next due value without removing it.
+//If no value is due yet, or the queue is nil, returns an error.
+func (dq *DelayQueue) Peek() (value interface{}, err error) {
+	if dq == nil {
+		return nil, errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.RLock()
+	defer dq.rwMutex.RUnlock()
+
+	if !dq.frontIsDue() {
+		return nil, errors.New("No value is due")
+	}
+	return dq.items[0].value, nil
+}
+
+//EnqueueAt adds a value that becomes available at the given time.
+//Panics on an uninitialized queue.
+func (dq *DelayQueue) EnqueueAt(value interface{}, due time.Time) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	dq.nextSeq++
+	heap.Push(&dq.items, &item{value: value, due: due, seq: dq.nextSeq})
+
+	close(dq.changed)
+	dq.changed = make(chan struct{})
+}
+
+//EnqueueAfter adds a value that becomes available once the delay passes.
+//Panics on an uninitialized queue.
+func (dq *DelayQueue) EnqueueAfter(value interface{}, delay time.Duration) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+	dq.EnqueueAt(value, dq.clock.Now().Add(delay))
+}
+
+//Dequeue removes the next due value. If no value is due yet, returns an error.
+//Panics on an uninitialized queue.
+func (dq *DelayQueue) Dequeue() (valueRemoved interface{}, err error) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	if !dq.frontIsDue() {
+		return nil, errors.New("No value is due")
+	}
+	return heap.Pop(&dq.items).(*item).value, nil
+}
+
+//DequeueWait removes the next due value, waiting until one matures or the context ends.
+//Returns the context error if the context ends first.
+//Panics on an uninitialized queue.
+func (dq *DelayQueue) DequeueWait(ctx context.Context) (valueRemoved interface{}, err error) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	for {
+		dq.rwMutex.Lock()
+		if dq.frontIsDue() {
+			value := heap.Pop(&dq.items).(*item).value
+			dq.rwMutex.Unlock()
+			return value, nil
+		}
+		var matured <-chan time.Time
+		if len(dq.items) > 0 {
+			matured = dq.clock.After(dq.items[0].due.Sub(dq.clock.Now()))
+		}
+		changed := dq.changed
+		dq.rwMutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		case <-matured:
+		}
+	}
+}
+
+//*************** Delay Queue Internal Structure ***************
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+type item struct {
+	value interface{}
+	due   time.Time
+	//Insertion order, breaks ties between values due at the same time
+	seq uint64
+}
+
+//frontIsDue reports whether the earliest value has matured. No locking.
+func (dq *DelayQueue) frontIsDue() bool {
+	return len(dq.items) > 0 && !dq.items[0].due.After(dq.clock.Now())
+}
+
+//itemHeap implements heap.Interface, ordered by due time and insertion order.
+type itemHeap []*item
+
+func (h itemHeap) Len() int {
+	return len(h)
+}
+
+func (h itemHeap) Less(i, j int) bool {
+	if !h[i].due.Equal(h[j].due) {
+		return h[i].due.Before(h[j].due)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h itemHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *itemHeap) Push(x interface{}) {
+	*h = append(*h, x.(*item))
+}
+
+func (h *itemHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return last
+}
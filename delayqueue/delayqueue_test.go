@@ -0,0 +1,223 @@
+package delayqueue_test
+
+import (
+	"context"
+	. "datatypes/delayqueue"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+//fakeClock only moves when advanced. After channels fire once the clock passes their deadline.
+type fakeClock struct {
+	now     time.Time
+	waiters []fakeWaiter
+	mutex   sync.Mutex
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	channel  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(duration time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	channel := make(chan time.Time, 1)
+	deadline := c.now.Add(duration)
+	if !deadline.After(c.now) {
+		channel <- c.now
+		return channel
+	}
+	c.waiters = append(c.waiters, fakeWaiter{deadline: deadline, channel: channel})
+	return channel
+}
+
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+	remaining := []fakeWaiter{}
+	for _, waiter := range c.waiters {
+		if !waiter.deadline.After(c.now) {
+			waiter.channel <- c.now
+		} else {
+			remaining = append(remaining, waiter)
+		}
+	}
+	c.waiters = remaining
+}
+
+//*************** Public Interface Test ***************
+
+func TestDequeueOrder(t *testing.T) {
+	clock := newFakeClock()
+	dq := NewDelayQueueWithClock(clock)
+
+	dq.EnqueueAfter("late", 3*time.Second)
+	dq.EnqueueAfter("early", time.Second)
+	dq.EnqueueAfter("tie 1", 2*time.Second)
+	dq.EnqueueAfter("tie 2", 2*time.Second)
+	dq.EnqueueAt("overdue", clock.Now().Add(-time.Second))
+
+	cases := []struct {
+		advance        time.Duration
+		expectedValues []interface{}
+	}{
+		{0, []interface{}{"overdue"}},
+		{time.Second, []interface{}{"early"}},
+		//Values due at the same time keep insertion order
+		{time.Second, []interface{}{"tie 1", "tie 2"}},
+		{10 * time.Second, []interface{}{"late"}},
+	}
+
+	for i, aCase := range cases {
+		clock.Advance(aCase.advance)
+		for j, expectedValue := range aCase.expectedValues {
+			value, err := dq.Dequeue()
+			if err != nil || value != expectedValue {
+				t.Errorf("Error in case %d, dequeue %d. Expected value %v, got %v (error: %v)", i, j, expectedValue, value, err)
+			}
+		}
+		//Nothing else is due yet
+		if _, err := dq.Dequeue(); err == nil {
+			t.Errorf("Error in case %d. Expected an error, got no error", i)
+		}
+	}
+	if dq.Length() != 0 {
+		t.Errorf("Expected an empty queue, got length %d", dq.Length())
+	}
+}
+
+func TestPeekAndNextDue(t *testing.T) {
+	clock := newFakeClock()
+	dq := NewDelayQueueWithClock(clock)
+
+	if _, err := dq.NextDue(); err == nil {
+		t.Errorf("Expected an error on an empty queue, got no error")
+	}
+	dq.EnqueueAfter("value", time.Minute)
+
+	due, err := dq.NextDue()
+	if err != nil || !due.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("Unexpected due time %v (error: %v)", due, err)
+	}
+	if _, err := dq.Peek(); err == nil {
+		t.Errorf("Expected an error peeking a value that is not due, got no error")
+	}
+	clock.Advance(time.Minute)
+	if value, err := dq.Peek(); err != nil || value != "value" || dq.Length() != 1 {
+		t.Errorf("Expected value to be due, got %v (error: %v)", value, err)
+	}
+}
+
+func TestDequeueWait(t *testing.T) {
+	clock := newFakeClock()
+	dq := NewDelayQueueWithClock(clock)
+	dq.EnqueueAfter("later", time.Hour)
+
+	result := make(chan interface{})
+	go func() {
+		value, _ := dq.DequeueWait(context.Background())
+		result <- value
+	}()
+
+	//A value due earlier wakes the consumer up
+	time.Sleep(10 * time.Millisecond)
+	dq.EnqueueAfter("sooner", time.Second)
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case value := <-result:
+		t.Fatalf("Value %v returned before it was due", value)
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case value := <-result:
+		if value != "sooner" {
+			t.Errorf("Expected value sooner, got %v", value)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("DequeueWait didn't return after the value matured")
+	}
+}
+
+func TestDequeueWaitCancel(t *testing.T) {
+	dq := NewDelayQueueWithClock(newFakeClock())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := dq.DequeueWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNilQueue(t *testing.T) {
+	var nilQueue *DelayQueue
+	if nilQueue.Length() != 0 {
+		t.Errorf("Nil queue should have zero length")
+	}
+	if _, err := nilQueue.Peek(); err == nil {
+		t.Errorf("Expected an error peeking a nil queue, got no error")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("EnqueueAfter on a nil queue should cause a panic, did not")
+		}
+	}()
+	nilQueue.EnqueueAfter(0, time.Second)
+}
+
+func Example() {
+	dq := NewDelayQueue()
+	dq.EnqueueAfter("retry request", time.Hour)
+	dq.EnqueueAfter("send reminder", 0)
+
+	value, err := dq.Dequeue()
+	if err != nil {
+		//Handle error...
+	}
+	fmt.Printf("Dequeued value: %v, length: %d", value, dq.Length())
+	//Output: Dequeued value: send reminder, length: 1
+}
+
+//*************** Concurrency Test ***************
+
+//TestConcurrency waits for values from multiple goroutines. Run with `go test -race` for better race detection.
+func TestConcurrency(t *testing.T) {
+	dq := NewDelayQueue()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dq.EnqueueAfter(i, time.Duration(i)*time.Microsecond)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := dq.DequeueWait(ctx); err != nil {
+				t.Errorf("Expected no error, got %s", err.Error())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if dq.Length() != 0 {
+		t.Errorf("Expected an empty queue, got length %d", dq.Length())
+	}
+}
@@ -0,0 +1,534 @@
+//Durablequeue is an implementation of a FIFO queue persisted to local disk.
+//Every Enqueue and Dequeue is appended to a segmented write-ahead log before it is applied in memory.
+//Opening a directory replays the log, so pending values survive a restart or a crash.
+//Fully consumed segments are deleted as the log rotates.
+//Safe to use concurrently.
+package durablequeue
+
+import (
+	"bufio"
+	"datatypes/binarycodec"
+	"datatypes/queue"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+//*************** Durable Queue Public Interface ***************
+
+//SyncPolicy controls when the log is flushed to stable storage.
+type SyncPolicy int
+
+const (
+	//SyncAlways flushes the log after every Enqueue and Dequeue. Nothing acknowledged is lost on a crash.
+	SyncAlways SyncPolicy = iota
+	//SyncInterval flushes the log periodically. A crash loses at most one interval of operations.
+	SyncInterval
+	//SyncNever leaves flushing to the operating system.
+	SyncNever
+)
+
+//Default values used for zero fields of Options.
+const (
+	DefaultSegmentSize  = 4 << 20
+	DefaultSyncInterval = time.Second
+)
+
+//Options configures a DurableQueue. The zero value is valid.
+type Options struct {
+	SyncPolicy SyncPolicy
+	//Period of flushes for SyncInterval.
+	SyncInterval time.Duration
+	//Size in bytes after which a new segment is started.
+	SegmentSize int64
+	//Encodes values written to the log. binarycodec.GobCodec is used when nil.
+	Codec binarycodec.Codec
+}
+
+//DurableQueue is a FIFO queue backed by a write-ahead log. Goroutine safe.
+type DurableQueue struct {
+	directory string
+	options   Options
+	values    *queue.Queue
+
+	segments   []segment
+	activeFile segmentFile
+	activeSize int64
+	//Set when a failed write couldn't be undone. Nothing may follow the torn record, so every later write fails.
+	logDamaged error
+
+	nextItemIndex uint64
+	consumed      uint64
+
+	closed   bool
+	dirty    bool
+	stopSync chan struct{}
+	syncDone chan struct{}
+	rwMutex  sync.RWMutex
+}
+
+//Open recovers the queue stored in the directory, or creates an empty one.
+//A partially written record at the end of the log, left by a crash, is discarded.
+func Open(directory string, options Options) (*DurableQueue, error) {
+	if options.SyncInterval <= 0 {
+		options.SyncInterval = DefaultSyncInterval
+	}
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = DefaultSegmentSize
+	}
+	if options.Codec == nil {
+		options.Codec = binarycodec.GobCodec{}
+	}
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	dq := &DurableQueue{directory: directory, options: options, values: queue.NewQueue()}
+	if err := dq.recover(); err != nil {
+		return nil, err
+	}
+	if err := dq.compact(); err != nil {
+		dq.activeFile.Close()
+		return nil, err
+	}
+
+	if options.SyncPolicy == SyncInterval {
+		dq.stopSync = make(chan struct{})
+		dq.syncDone = make(chan struct{})
+		go dq.syncPeriodically()
+	}
+	return dq, nil
+}
+
+//Length returns the current number of values in the queue. Returns 0 on an uninitialized DurableQueue.
+func (dq *DurableQueue) Length() int {
+	if dq == nil {
+		return 0
+	}
+
+	dq.rwMutex.RLock()
+	defer dq.rwMutex.RUnlock()
+
+	return dq.values.Length()
+}
+
+//Peek returns the value at the front of the queue without removing it.
+//If the queue is empty, closed or nil, returns an error.
+func (dq *DurableQueue) Peek() (value interface{}, err error) {
+	if dq == nil {
+		return nil, errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.RLock()
+	defer dq.rwMutex.RUnlock()
+
+	if dq.closed {
+		return nil, errors.New("Queue is closed")
+	}
+	return dq.values.Peek()
+}
+
+//Enqueue logs the value and adds it to the back of the queue.
+//Returns an error if the value can't be encoded or written, the queue is unchanged in that case.
+//Panics on an uninitialized queue.
+func (dq *DurableQueue) Enqueue(value interface{}) error {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	if dq.closed {
+		return errors.New("Queue is closed")
+	}
+
+	encodedValue, err := dq.options.Codec.EncodeElement(value)
+	if err != nil {
+		return err
+	}
+	if err := dq.writeRecord(encodeRecord(recordEnqueue, encodedValue)); err != nil {
+		return err
+	}
+
+	dq.values.Enqueue(value)
+	dq.nextItemIndex++
+	return nil
+}
+
+//Dequeue logs the removal and removes the value from the front of the queue.
+//If queue is empty or closed, or the log can't be written, returns an error.
+//Panics on an uninitialized queue.
+func (dq *DurableQueue) Dequeue() (valueRemoved interface{}, err error) {
+	if dq == nil {
+		panic("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	if dq.closed {
+		return nil, errors.New("Queue is closed")
+	}
+	if dq.values.Length() == 0 {
+		return nil, errors.New("Queue is already empty")
+	}
+
+	if err := dq.writeRecord(encodeRecord(recordDequeue, nil)); err != nil {
+		return nil, err
+	}
+
+	dq.consumed++
+	return dq.values.Dequeue()
+}
+
+//Sync flushes the log to stable storage. Only needed with SyncInterval and SyncNever policies.
+func (dq *DurableQueue) Sync() error {
+	if dq == nil {
+		return errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	if dq.closed {
+		return errors.New("Queue is closed")
+	}
+	return dq.syncActive()
+}
+
+//Compact deletes segments that only hold consumed values. Happens automatically whenever a new segment is started.
+func (dq *DurableQueue) Compact() error {
+	if dq == nil {
+		return errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	defer dq.rwMutex.Unlock()
+
+	if dq.closed {
+		return errors.New("Queue is closed")
+	}
+	return dq.compact()
+}
+
+//Close flushes and closes the log. The queue can't be modified afterwards, reopen the directory instead.
+func (dq *DurableQueue) Close() error {
+	if dq == nil {
+		return errors.New("Queue is nil")
+	}
+
+	dq.rwMutex.Lock()
+	if dq.closed {
+		dq.rwMutex.Unlock()
+		return errors.New("Queue is already closed")
+	}
+	dq.closed = true
+	syncErr := dq.activeFile.Sync()
+	closeErr := dq.activeFile.Close()
+	dq.rwMutex.Unlock()
+
+	//Wait for the sync goroutine without holding the lock it needs
+	if dq.stopSync != nil {
+		close(dq.stopSync)
+		<-dq.syncDone
+	}
+
+	if syncErr != nil {
+		return syncErr
+	}
+	return closeErr
+}
+
+//*************** Durable Queue Internal Structure ***************
+
+//segmentFile is the part of *os.File used for the active segment.
+type segmentFile interface {
+	io.Writer
+	Sync() error
+	Close() error
+	Truncate(size int64) error
+}
+
+//Make runtime asserts fatal
+const (
+	panic_on_internal_inconsistencies = true
+)
+
+//pendingItem is a value found in the log during recovery, together with its index.
+type pendingItem struct {
+	index uint64
+	value interface{}
+}
+
+//recover replays all segments and opens the last one for appending.
+func (dq *DurableQueue) recover() error {
+	segments, err := listSegments(dq.directory)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return dq.startSegment(0)
+	}
+
+	items := []pendingItem{}
+	for i := range segments {
+		isLast := i == len(segments)-1
+		items, err = dq.replaySegment(&segments[i], i == 0, isLast, items)
+		if err != nil {
+			return err
+		}
+	}
+	if dq.consumed > dq.nextItemIndex {
+		return errors.New("Log is corrupted - more values dequeued than enqueued")
+	}
+
+	for _, item := range items {
+		if item.index >= dq.consumed {
+			dq.values.Enqueue(item.value)
+		}
+	}
+	if uint64(dq.values.Length()) != dq.nextItemIndex-dq.consumed {
+		return errors.New("Log is corrupted - values are missing from deleted segments")
+	}
+
+	last := segments[len(segments)-1]
+	activeFile, err := os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := activeFile.Stat()
+	if err != nil {
+		activeFile.Close()
+		return err
+	}
+	dq.segments = segments
+	dq.activeFile = activeFile
+	dq.activeSize = info.Size()
+	return nil
+}
+
+//replaySegment applies the records of one segment. A damaged tail of the last segment is truncated,
+//damage anywhere else is reported as an error.
+func (dq *DurableQueue) replaySegment(seg *segment, isFirst bool, isLast bool, items []pendingItem) ([]pendingItem, error) {
+	file, err := os.Open(seg.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	recordType, payload, size, err := readRecord(reader)
+	if err == nil && recordType == recordHeader {
+		seg.header, err = decodeHeader(payload)
+	} else if err == nil {
+		err = errTruncatedRecord
+	}
+	if err != nil {
+		//A crash right after a segment was created leaves it without a header
+		if isLast && (err == io.EOF || err == errTruncatedRecord) {
+			seg.header = segmentHeader{firstItemIndex: dq.nextItemIndex, consumed: dq.consumed}
+			return items, rewriteHeader(seg.path, seg.header)
+		}
+		return nil, fmt.Errorf("Can't read header of segment %s - %v", seg.path, err)
+	}
+
+	if isFirst {
+		dq.nextItemIndex = seg.header.firstItemIndex
+		dq.consumed = seg.header.consumed
+	} else if seg.header.firstItemIndex != dq.nextItemIndex || seg.header.consumed != dq.consumed {
+		return nil, fmt.Errorf("Segment %s doesn't continue the previous segment", seg.path)
+	}
+
+	offset := size
+	for {
+		recordType, payload, size, err := readRecord(reader)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err == errTruncatedRecord && isLast {
+			return items, os.Truncate(seg.path, offset)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Segment %s is corrupted at offset %d - %v", seg.path, offset, err)
+		}
+
+		switch recordType {
+		case recordEnqueue:
+			value, err := dq.options.Codec.DecodeElement(payload)
+			if err != nil {
+				return nil, fmt.Errorf("Can't decode value in segment %s at offset %d - %s", seg.path, offset, err.Error())
+			}
+			items = append(items, pendingItem{index: dq.nextItemIndex, value: value})
+			dq.nextItemIndex++
+		case recordDequeue:
+			dq.consumed++
+		default:
+			return nil, fmt.Errorf("Unexpected record type %d in segment %s at offset %d", recordType, seg.path, offset)
+		}
+		offset += size
+	}
+}
+
+func rewriteHeader(path string, header segmentHeader) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(encodeHeader(header)); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+//startSegment creates a new segment with the given id and makes it the active one. No locking.
+func (dq *DurableQueue) startSegment(id uint64) error {
+	header := segmentHeader{firstItemIndex: dq.nextItemIndex, consumed: dq.consumed}
+	path := segmentPath(dq.directory, id)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	headerRecord := encodeHeader(header)
+	if _, err := file.Write(headerRecord); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	syncDirectory(dq.directory)
+
+	dq.segments = append(dq.segments, segment{id: id, path: path, header: header})
+	dq.activeFile = file
+	dq.activeSize = int64(len(headerRecord))
+	dq.dirty = false
+	return nil
+}
+
+//writeRecord appends a record to the active segment, starting a new one when it is full.
+//A partially written record is truncated away, otherwise recovery would stop at it and lose every record after it. No locking.
+func (dq *DurableQueue) writeRecord(record []byte) error {
+	if dq.logDamaged != nil {
+		return dq.logDamaged
+	}
+	if dq.activeSize >= dq.options.SegmentSize {
+		if err := dq.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := dq.activeFile.Write(record)
+	if err != nil {
+		if written > 0 {
+			dq.undoWrite()
+		}
+		return err
+	}
+
+	//The caller doesn't apply a record that failed to sync, so it must not be replayed either
+	if dq.options.SyncPolicy == SyncAlways {
+		if err := dq.activeFile.Sync(); err != nil {
+			dq.undoWrite()
+			return err
+		}
+	} else {
+		dq.dirty = true
+	}
+	dq.activeSize += int64(written)
+	return nil
+}
+
+//undoWrite truncates the active segment back to its size before the failed write.
+//If that fails too, the log is marked damaged. No locking.
+func (dq *DurableQueue) undoWrite() {
+	if err := dq.activeFile.Truncate(dq.activeSize); err != nil {
+		dq.logDamaged = fmt.Errorf("Log is damaged - can't remove a failed record: %v", err)
+	}
+}
+
+func (dq *DurableQueue) rotate() error {
+	if err := dq.activeFile.Sync(); err != nil {
+		return err
+	}
+	if err := dq.activeFile.Close(); err != nil {
+		return err
+	}
+
+	if len(dq.segments) == 0 && panic_on_internal_inconsistencies {
+		panic("Active segment is missing from the segment list")
+	}
+	lastId := dq.segments[len(dq.segments)-1].id
+	if err := dq.startSegment(lastId + 1); err != nil {
+		return err
+	}
+	return dq.compact()
+}
+
+//compact deletes the oldest segments while every value in them has been consumed.
+//The dequeue records they hold are accounted for by the header of the following segment. No locking.
+func (dq *DurableQueue) compact() error {
+	//Dequeue records have to be durable before the values they consumed are deleted
+	if err := dq.syncActive(); err != nil {
+		return err
+	}
+	for len(dq.segments) > 1 && dq.consumed >= dq.segments[1].header.firstItemIndex {
+		if err := os.Remove(dq.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		dq.segments = dq.segments[1:]
+	}
+	return nil
+}
+
+func (dq *DurableQueue) syncActive() error {
+	if !dq.dirty {
+		return nil
+	}
+	if err := dq.activeFile.Sync(); err != nil {
+		return err
+	}
+	dq.dirty = false
+	return nil
+}
+
+func (dq *DurableQueue) syncPeriodically() {
+	defer close(dq.syncDone)
+
+	ticker := time.NewTicker(dq.options.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-dq.stopSync:
+			return
+		case <-ticker.C:
+			dq.rwMutex.Lock()
+			if !dq.closed {
+				dq.syncActive()
+			}
+			dq.rwMutex.Unlock()
+		}
+	}
+}
+
+//syncDirectory makes segment creation durable. Best effort, not supported on every platform.
+func syncDirectory(directory string) {
+	dir, err := os.Open(directory)
+	if err != nil {
+		return
+	}
+	dir.Sync()
+	dir.Close()
+}
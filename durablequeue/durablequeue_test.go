@@ -0,0 +1,282 @@
+package durablequeue_test
+
+import (
+	. "datatypes/durablequeue"
+	"datatypes/linearizability"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openQueue(t *testing.T, directory string, options Options) *DurableQueue {
+	dq, err := Open(directory, options)
+	if err != nil {
+		t.Fatalf("Failed to open queue in %s: %s", directory, err.Error())
+	}
+	return dq
+}
+
+func segmentFiles(t *testing.T, directory string) []string {
+	files, err := filepath.Glob(filepath.Join(directory, "*.wal"))
+	if err != nil {
+		t.Fatalf("Failed to list segments: %s", err.Error())
+	}
+	return files
+}
+
+//Dequeues all values and checks they match the expectation.
+func expectValues(t *testing.T, dq *DurableQueue, expectedValues []interface{}) {
+	if length := dq.Length(); length != len(expectedValues) {
+		t.Fatalf("Expected length %d, got %d", len(expectedValues), length)
+	}
+	for i, expectedValue := range expectedValues {
+		value, err := dq.Dequeue()
+		if err != nil {
+			t.Fatalf("Dequeue %d. Expected no error, got %s", i, err.Error())
+		}
+		if value != expectedValue {
+			t.Errorf("Dequeue %d. Expected value %v, got %v", i, expectedValue, value)
+		}
+	}
+}
+
+//*************** Public Interface Test ***************
+
+func TestRecovery(t *testing.T) {
+	directory := t.TempDir()
+
+	dq := openQueue(t, directory, Options{})
+	for _, value := range []interface{}{"first", 2, "third"} {
+		if err := dq.Enqueue(value); err != nil {
+			t.Fatalf("Expected no error, got %s", err.Error())
+		}
+	}
+	if value, err := dq.Dequeue(); err != nil || value != "first" {
+		t.Fatalf("Expected value first, got %v (error: %v)", value, err)
+	}
+	if err := dq.Close(); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	reopened := openQueue(t, directory, Options{})
+	defer reopened.Close()
+	if value, err := reopened.Peek(); err != nil || value != 2 {
+		t.Errorf("Expected front value 2, got %v (error: %v)", value, err)
+	}
+	expectValues(t, reopened, []interface{}{2, "third"})
+	if _, err := reopened.Dequeue(); err == nil {
+		t.Errorf("Expected an error dequeuing an empty queue, got no error")
+	}
+}
+
+func TestClosedQueue(t *testing.T) {
+	dq := openQueue(t, t.TempDir(), Options{})
+	dq.Enqueue(1)
+	dq.Close()
+
+	if err := dq.Enqueue(2); err == nil {
+		t.Errorf("Expected an error enqueuing into a closed queue, got no error")
+	}
+	if _, err := dq.Dequeue(); err == nil {
+		t.Errorf("Expected an error dequeuing from a closed queue, got no error")
+	}
+	if _, err := dq.Peek(); err == nil {
+		t.Errorf("Expected an error peeking into a closed queue, got no error")
+	}
+	if err := dq.Close(); err == nil {
+		t.Errorf("Expected an error closing twice, got no error")
+	}
+
+	var nilQueue *DurableQueue
+	if nilQueue.Length() != 0 {
+		t.Errorf("Nil queue should have zero length")
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Enqueue on a nil queue should cause a panic, did not")
+		}
+	}()
+	nilQueue.Enqueue(0)
+}
+
+func TestSegmentCompaction(t *testing.T) {
+	directory := t.TempDir()
+	options := Options{SegmentSize: 128, SyncPolicy: SyncNever}
+
+	dq := openQueue(t, directory, options)
+	for i := 0; i < 200; i++ {
+		dq.Enqueue(i)
+	}
+	segmentsBeforeDequeue := len(segmentFiles(t, directory))
+	for i := 0; i < 190; i++ {
+		dq.Dequeue()
+	}
+	//Writing more records rotates the log and deletes consumed segments
+	for i := 200; i < 220; i++ {
+		dq.Enqueue(i)
+	}
+	dq.Compact()
+	segmentsAfterCompaction := len(segmentFiles(t, directory))
+	if segmentsAfterCompaction >= segmentsBeforeDequeue {
+		t.Errorf("Expected fewer than %d segments after compaction, got %d", segmentsBeforeDequeue, segmentsAfterCompaction)
+	}
+	dq.Close()
+
+	reopened := openQueue(t, directory, options)
+	defer reopened.Close()
+	expectedValues := []interface{}{}
+	for i := 190; i < 220; i++ {
+		expectedValues = append(expectedValues, i)
+	}
+	expectValues(t, reopened, expectedValues)
+}
+
+func TestTruncatedTail(t *testing.T) {
+	directory := t.TempDir()
+	dq := openQueue(t, directory, Options{})
+	dq.Enqueue("kept")
+	dq.Close()
+
+	//Simulate a crash in the middle of writing a record
+	files := segmentFiles(t, directory)
+	file, err := os.OpenFile(files[len(files)-1], os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open segment: %s", err.Error())
+	}
+	file.Write([]byte{2, 50, 1, 2, 3})
+	file.Close()
+
+	reopened := openQueue(t, directory, Options{})
+	if err := reopened.Enqueue("after crash"); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	reopened.Close()
+
+	reopened = openQueue(t, directory, Options{})
+	defer reopened.Close()
+	expectValues(t, reopened, []interface{}{"kept", "after crash"})
+}
+
+func TestCorruptedSegment(t *testing.T) {
+	directory := t.TempDir()
+	dq := openQueue(t, directory, Options{SegmentSize: 64})
+	for i := 0; i < 20; i++ {
+		dq.Enqueue(i)
+	}
+	dq.Close()
+
+	//Damage in a segment that isn't the last one can't be caused by a crash
+	files := segmentFiles(t, directory)
+	if len(files) < 2 {
+		t.Fatalf("Expected several segments, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(files[0], data, 0644)
+
+	if _, err := Open(directory, Options{SegmentSize: 64}); err == nil {
+		t.Errorf("Expected an error opening a corrupted log, got no error")
+	}
+}
+
+func TestSyncInterval(t *testing.T) {
+	directory := t.TempDir()
+	dq := openQueue(t, directory, Options{SyncPolicy: SyncInterval, SyncInterval: time.Millisecond})
+	dq.Enqueue("value")
+	time.Sleep(5 * time.Millisecond)
+	if err := dq.Sync(); err != nil {
+		t.Errorf("Expected no error, got %s", err.Error())
+	}
+	if err := dq.Close(); err != nil {
+		t.Errorf("Expected no error, got %s", err.Error())
+	}
+
+	reopened := openQueue(t, directory, Options{})
+	defer reopened.Close()
+	expectValues(t, reopened, []interface{}{"value"})
+}
+
+func Example() {
+	directory, _ := os.MkdirTemp("", "durablequeue")
+	defer os.RemoveAll(directory)
+
+	jobs, _ := Open(directory, Options{SyncPolicy: SyncAlways})
+	jobs.Enqueue("send email")
+	jobs.Enqueue("resize image")
+	jobs.Close()
+
+	//After a restart the pending jobs are still there
+	jobs, _ = Open(directory, Options{})
+	value, _ := jobs.Dequeue()
+	fmt.Printf("Dequeued value: %v, length: %d", value, jobs.Length())
+	jobs.Close()
+	//Output: Dequeued value: send email, length: 1
+}
+
+//*************** Concurrency Test ***************
+
+//TestConcurrency accesses the DurableQueue from multiple goroutines. Run with `go test -race` for better race detection.
+func TestConcurrency(t *testing.T) {
+	directory := t.TempDir()
+	options := Options{SyncPolicy: SyncInterval, SyncInterval: time.Millisecond, SegmentSize: 256}
+	dq := openQueue(t, directory, options)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dq.Enqueue("-")
+			dq.Peek()
+			dq.Dequeue()
+			dq.Length()
+		}()
+	}
+	wg.Wait()
+	dq.Enqueue("last")
+	dq.Close()
+
+	reopened := openQueue(t, directory, options)
+	defer reopened.Close()
+	expectValues(t, reopened, []interface{}{"last"})
+}
+
+//TestLinearizability records concurrent operations on a DurableQueue and checks them against a sequential FIFO model.
+func TestLinearizability(t *testing.T) {
+	dq := openQueue(t, t.TempDir(), Options{SegmentSize: 256})
+	defer dq.Close()
+	recorder := linearizability.NewRecorder()
+	var wg sync.WaitGroup
+
+	for client := 0; client < 4; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				value := fmt.Sprintf("%d-%d", client, i)
+				recorder.Record(client, linearizability.Input{Op: linearizability.Enqueue, Value: value}, func() interface{} {
+					return linearizability.Output{Err: dq.Enqueue(value) != nil}
+				})
+				recorder.Record(client, linearizability.Input{Op: linearizability.Peek}, func() interface{} {
+					value, err := dq.Peek()
+					return linearizability.Output{Value: value, Err: err != nil}
+				})
+				recorder.Record(client, linearizability.Input{Op: linearizability.Dequeue}, func() interface{} {
+					value, err := dq.Dequeue()
+					return linearizability.Output{Value: value, Err: err != nil}
+				})
+				recorder.Record(client, linearizability.Input{Op: linearizability.Length}, func() interface{} {
+					return linearizability.Output{Value: dq.Length()}
+				})
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	if !linearizability.CheckOperations(linearizability.QueueModel, recorder.History()) {
+		t.Errorf("Concurrent DurableQueue history is not linearizable")
+	}
+}
@@ -0,0 +1,159 @@
+package durablequeue
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//*************** Write-Ahead Log ***************
+
+//Every segment starts with a header record, followed by enqueue and dequeue records.
+//Record layout: type byte, uvarint payload length, payload, CRC-32 of everything before it (little endian).
+const (
+	recordHeader  byte = 1
+	recordEnqueue byte = 2
+	recordDequeue byte = 3
+)
+
+const segmentExtension = ".wal"
+
+//errTruncatedRecord marks a partially written record, the expected result of a crash during a write.
+var errTruncatedRecord = errors.New("Record is truncated or corrupted")
+
+//segmentHeader is the payload of a header record.
+//Items get consecutive indexes in the order they are enqueued; consumed is the number of items dequeued so far.
+type segmentHeader struct {
+	firstItemIndex uint64
+	consumed       uint64
+}
+
+type segment struct {
+	id     uint64
+	path   string
+	header segmentHeader
+}
+
+func segmentPath(directory string, id uint64) string {
+	return filepath.Join(directory, fmt.Sprintf("%020d%s", id, segmentExtension))
+}
+
+//listSegments returns the paths of all segments in the directory, oldest first.
+func listSegments(directory string) ([]segment, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []segment{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExtension) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{id: id, path: filepath.Join(directory, name)})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].id < segments[j].id })
+	return segments, nil
+}
+
+func encodeRecord(recordType byte, payload []byte) []byte {
+	buffer := bytes.Buffer{}
+	buffer.WriteByte(recordType)
+	writeUvarint(&buffer, uint64(len(payload)))
+	buffer.Write(payload)
+
+	checksum := make([]byte, 4)
+	binary.LittleEndian.PutUint32(checksum, crc32.ChecksumIEEE(buffer.Bytes()))
+	buffer.Write(checksum)
+	return buffer.Bytes()
+}
+
+func encodeHeader(header segmentHeader) []byte {
+	buffer := bytes.Buffer{}
+	writeUvarint(&buffer, header.firstItemIndex)
+	writeUvarint(&buffer, header.consumed)
+	return encodeRecord(recordHeader, buffer.Bytes())
+}
+
+func decodeHeader(payload []byte) (segmentHeader, error) {
+	reader := bytes.NewReader(payload)
+	firstItemIndex, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return segmentHeader{}, errors.New("Segment header is corrupted")
+	}
+	consumed, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return segmentHeader{}, errors.New("Segment header is corrupted")
+	}
+	return segmentHeader{firstItemIndex: firstItemIndex, consumed: consumed}, nil
+}
+
+//readRecord reads the next record. Returns io.EOF at a clean end of the segment,
+//errTruncatedRecord if the remaining bytes don't form a valid record.
+func readRecord(reader *bufio.Reader) (recordType byte, payload []byte, size int64, err error) {
+	recordType, err = reader.ReadByte()
+	if err == io.EOF {
+		return 0, nil, 0, io.EOF
+	}
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	counting := &countingByteReader{reader: reader}
+	payloadLength, err := binary.ReadUvarint(counting)
+	if err != nil || payloadLength > maxRecordPayload {
+		return 0, nil, 0, errTruncatedRecord
+	}
+
+	rest := make([]byte, int(payloadLength)+4)
+	if _, err := io.ReadFull(reader, rest); err != nil {
+		return 0, nil, 0, errTruncatedRecord
+	}
+	payload = rest[:payloadLength]
+
+	checked := bytes.Buffer{}
+	checked.WriteByte(recordType)
+	writeUvarint(&checked, payloadLength)
+	checked.Write(payload)
+	if crc32.ChecksumIEEE(checked.Bytes()) != binary.LittleEndian.Uint32(rest[payloadLength:]) {
+		return 0, nil, 0, errTruncatedRecord
+	}
+
+	return recordType, payload, int64(1 + counting.count + len(rest)), nil
+}
+
+//Upper bound on a single record, protects recovery from allocating huge buffers for corrupted lengths.
+const maxRecordPayload = 1 << 30
+
+type countingByteReader struct {
+	reader *bufio.Reader
+	count  int
+}
+
+func (c *countingByteReader) ReadByte() (byte, error) {
+	b, err := c.reader.ReadByte()
+	if err == nil {
+		c.count++
+	}
+	return b, err
+}
+
+func writeUvarint(buffer *bytes.Buffer, value uint64) {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	size := binary.PutUvarint(encoded, value)
+	buffer.Write(encoded[:size])
+}
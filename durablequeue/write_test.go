@@ -0,0 +1,97 @@
+package durablequeue
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+//failingFile writes only half of a record while failWrites is set, and fails syncs while failSyncs is set.
+type failingFile struct {
+	*os.File
+	failWrites    bool
+	failSyncs     bool
+	failTruncates bool
+}
+
+func (f *failingFile) Write(data []byte) (int, error) {
+	if !f.failWrites {
+		return f.File.Write(data)
+	}
+	written, _ := f.File.Write(data[:len(data)/2])
+	return written, errors.New("Disk is full")
+}
+
+func (f *failingFile) Sync() error {
+	if f.failSyncs {
+		return errors.New("Disk can't flush")
+	}
+	return f.File.Sync()
+}
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.failTruncates {
+		return errors.New("Disk is gone")
+	}
+	return f.File.Truncate(size)
+}
+
+//*************** Failed Write Test ***************
+
+func TestFailedWrite(t *testing.T) {
+	cases := []struct {
+		failSync       bool
+		failTruncates  bool
+		expectedValues []interface{}
+	}{
+		//The torn record is removed and later records are kept
+		{failTruncates: false, expectedValues: []interface{}{1, 3}},
+		//The torn record stays at the end of the log, later writes are refused
+		{failTruncates: true, expectedValues: []interface{}{1}},
+		//A complete record that failed to sync is removed like a torn one
+		{failSync: true, expectedValues: []interface{}{1, 3}},
+	}
+
+	for i, aCase := range cases {
+		directory := t.TempDir()
+		dq, err := Open(directory, Options{})
+		if err != nil {
+			t.Fatalf("Error in case %d. Failed to open queue: %s", i, err.Error())
+		}
+		file := &failingFile{File: dq.activeFile.(*os.File), failTruncates: aCase.failTruncates}
+		dq.activeFile = file
+
+		dq.Enqueue(1)
+		file.failWrites, file.failSyncs = !aCase.failSync, aCase.failSync
+		if err := dq.Enqueue(2); err == nil {
+			t.Errorf("Error in case %d. Expected the failed write to return an error", i)
+		}
+		if dq.Length() != 1 {
+			t.Errorf("Error in case %d. Expected the failed value not to be queued", i)
+		}
+		file.failWrites, file.failSyncs = false, false
+		if err := dq.Enqueue(3); (err != nil) != aCase.failTruncates {
+			t.Errorf("Error in case %d. Unexpected result of the write after the failed one: %v", i, err)
+		}
+		dq.Close()
+
+		reopened, err := Open(directory, Options{})
+		if err != nil {
+			t.Fatalf("Error in case %d. Failed to reopen queue: %s", i, err.Error())
+		}
+		values := []interface{}{}
+		for reopened.Length() > 0 {
+			value, _ := reopened.Dequeue()
+			values = append(values, value)
+		}
+		reopened.Close()
+		if len(values) != len(aCase.expectedValues) {
+			t.Fatalf("Error in case %d. Expected %v, got %v", i, aCase.expectedValues, values)
+		}
+		for j := range values {
+			if values[j] != aCase.expectedValues[j] {
+				t.Errorf("Error in case %d. Expected %v, got %v", i, aCase.expectedValues, values)
+			}
+		}
+	}
+}
@@ -0,0 +1,277 @@
+//Fairqueue is an implementation of a multi-tenant FIFO queue.
+//Values are kept in a queue.Queue per key, Dequeue takes turns between the keys that have values,
+//so one key flooding the queue can't starve the others.
+//Turns are plain round robin, weighted round robin or deficit round robin.
+//Sub-queues are removed as soon as they run empty.
+//Safe to use concurrently.
+package fairqueue
+
+import (
+	"datatypes/queue"
+	"errors"
+	"sync"
+)
+
+//*************** Fair Queue Public Interface ***************
+
+//Scheduling selects how turns are shared between keys.
+type Scheduling int
+
+const (
+	//Every key gets one value per turn.
+	RoundRobin Scheduling = iota
+	//Every key gets as many values per turn as its weight.
+	WeightedRoundRobin
+	//Every key earns Quantum times its weight per turn and spends it on the cost of its values.
+	DeficitRoundRobin
+)
+
+//Options configures a FairQueue. The zero value is plain round robin with no caps.
+type Options struct {
+	Scheduling Scheduling
+	//Weight of keys without an explicit weight. Values below 1 mean 1.
+	DefaultWeight int
+	//Maximum number of values held per key. Zero means no limit.
+	MaxPerKey int
+	//Credit earned per turn and unit of weight in deficit round robin. Values below 1 mean 1.
+	Quantum int
+	//Cost of a value in deficit round robin. Every value costs 1 when nil, costs below 1 count as 1.
+	Cost func(value interface{}) int
+}
+
+//FairQueue is a set of FIFO sub-queues served in turns. Goroutine safe.
+type FairQueue struct {
+	options Options
+	//Non-empty sub-queues by key
+	tenants map[string]*tenant
+	//Non-empty sub-queues in turn order, the one at the front is served next
+	turns *queue.Queue
+	//Explicit weights and caps, kept when a sub-queue is removed
+	weights map[string]int
+	caps    map[string]int
+	length  int
+	mutex   sync.Mutex
+}
+
+//NewFairQueue initializes an empty FairQueue. Recommended way of initialization.
+func NewFairQueue(options Options) *FairQueue {
+	if options.DefaultWeight < 1 {
+		options.DefaultWeight = 1
+	}
+	if options.Quantum < 1 {
+		options.Quantum = 1
+	}
+	return &FairQueue{
+		options: options,
+		tenants: map[string]*tenant{},
+		turns:   queue.NewQueue(),
+		weights: map[string]int{},
+		caps:    map[string]int{},
+	}
+}
+
+//Length returns the number of values across all keys. Returns 0 on an uninitialized FairQueue.
+func (fq *FairQueue) Length() int {
+	if fq == nil {
+		return 0
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	return fq.length
+}
+
+//KeyLength returns the number of values held for a key.
+func (fq *FairQueue) KeyLength(key string) int {
+	if fq == nil {
+		return 0
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	if aTenant, ok := fq.tenants[key]; ok {
+		return aTenant.values.Length()
+	}
+	return 0
+}
+
+//Keys returns the number of keys holding at least one value.
+func (fq *FairQueue) Keys() int {
+	if fq == nil {
+		return 0
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	return len(fq.tenants)
+}
+
+//SetWeight sets the weight of a key. Values below 1 restore the default weight.
+//Takes effect from the key's next turn.
+//Panics on an uninitialized queue.
+func (fq *FairQueue) SetWeight(key string, weight int) {
+	if fq == nil {
+		panic("Queue is nil")
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	if weight < 1 {
+		delete(fq.weights, key)
+		return
+	}
+	fq.weights[key] = weight
+}
+
+//SetCap sets the maximum number of values held for a key, overriding Options.MaxPerKey.
+//Values below 1 restore the default cap. Values already held are kept.
+//Panics on an uninitialized queue.
+func (fq *FairQueue) SetCap(key string, capacity int) {
+	if fq == nil {
+		panic("Queue is nil")
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	if capacity < 1 {
+		delete(fq.caps, key)
+		return
+	}
+	fq.caps[key] = capacity
+}
+
+//Enqueue adds value to the back of the key's sub-queue.
+//If the key is at its cap, returns an error and drops the value.
+//Panics on an uninitialized queue.
+func (fq *FairQueue) Enqueue(key string, value interface{}) error {
+	if fq == nil {
+		panic("Queue is nil")
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	aTenant, ok := fq.tenants[key]
+	if !ok {
+		aTenant = &tenant{key: key, values: queue.NewQueue()}
+	}
+	if capacity := fq.capOf(key); capacity > 0 && aTenant.values.Length() >= capacity {
+		return errors.New("Key is at capacity")
+	}
+
+	aTenant.values.Enqueue(value)
+	fq.length++
+	if !ok {
+		fq.tenants[key] = aTenant
+		fq.turns.Enqueue(aTenant)
+	}
+	return nil
+}
+
+//Dequeue removes the next value in turn order and returns it with its key.
+//If the queue is empty, returns an error.
+//Panics on an uninitialized queue.
+func (fq *FairQueue) Dequeue() (key string, valueRemoved interface{}, err error) {
+	if fq == nil {
+		panic("Queue is nil")
+	}
+
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	for {
+		front, err := fq.turns.Peek()
+		if err != nil {
+			return "", nil, errors.New("Queue is empty")
+		}
+		aTenant := front.(*tenant)
+
+		if !aTenant.inTurn {
+			aTenant.inTurn = true
+			switch fq.options.Scheduling {
+			case DeficitRoundRobin:
+				aTenant.credit += fq.options.Quantum * fq.weightOf(aTenant.key)
+			case WeightedRoundRobin:
+				aTenant.credit = fq.weightOf(aTenant.key)
+			default:
+				aTenant.credit = 1
+			}
+		}
+
+		cost := 1
+		if fq.options.Scheduling == DeficitRoundRobin {
+			head, _ := aTenant.values.Peek()
+			cost = fq.costOf(head)
+		}
+		//Not enough credit left for the value at the front - the turn passes to the next key
+		if cost > aTenant.credit {
+			fq.endTurn(aTenant)
+			continue
+		}
+
+		value, _ := aTenant.values.Dequeue()
+		aTenant.credit -= cost
+		fq.length--
+		if aTenant.values.Length() == 0 {
+			fq.removeTenant(aTenant)
+		} else if aTenant.credit == 0 {
+			fq.endTurn(aTenant)
+		}
+		return aTenant.key, value, nil
+	}
+}
+
+//*************** Fair Queue Internal Structure ***************
+
+type tenant struct {
+	key    string
+	values *queue.Queue
+	//Whether the tenant is being served, and what it can still take during this turn
+	inTurn bool
+	credit int
+}
+
+//endTurn moves the tenant at the front to the back of the turn order. No locking.
+func (fq *FairQueue) endTurn(aTenant *tenant) {
+	aTenant.inTurn = false
+	if fq.options.Scheduling != DeficitRoundRobin {
+		aTenant.credit = 0
+	}
+	fq.turns.Dequeue()
+	fq.turns.Enqueue(aTenant)
+}
+
+//removeTenant drops the empty tenant at the front. Unused deficit is not carried over. No locking.
+func (fq *FairQueue) removeTenant(aTenant *tenant) {
+	fq.turns.Dequeue()
+	delete(fq.tenants, aTenant.key)
+}
+
+func (fq *FairQueue) weightOf(key string) int {
+	if weight, ok := fq.weights[key]; ok {
+		return weight
+	}
+	return fq.options.DefaultWeight
+}
+
+func (fq *FairQueue) capOf(key string) int {
+	if capacity, ok := fq.caps[key]; ok {
+		return capacity
+	}
+	return fq.options.MaxPerKey
+}
+
+func (fq *FairQueue) costOf(value interface{}) int {
+	if fq.options.Cost == nil {
+		return 1
+	}
+	if cost := fq.options.Cost(value); cost > 1 {
+		return cost
+	}
+	return 1
+}
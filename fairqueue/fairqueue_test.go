@@ -0,0 +1,177 @@
+package fairqueue_test
+
+import (
+	. "datatypes/fairqueue"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//dequeueKeys drains the queue and returns the keys in the order they were served.
+func dequeueKeys(fq *FairQueue) string {
+	keys := []string{}
+	for {
+		key, _, err := fq.Dequeue()
+		if err != nil {
+			return strings.Join(keys, "")
+		}
+		keys = append(keys, key)
+	}
+}
+
+func fill(fq *FairQueue, key string, count int) {
+	for i := 0; i < count; i++ {
+		fq.Enqueue(key, i)
+	}
+}
+
+//*************** Public Interface Test ***************
+
+func TestScheduling(t *testing.T) {
+	cases := []struct {
+		options       Options
+		weights       map[string]int
+		expectedOrder string
+	}{
+		{options: Options{}, expectedOrder: "abcabaaa"},
+		//Weights don't apply to plain round robin, every key still gets one value per turn
+		{options: Options{Scheduling: RoundRobin, DefaultWeight: 2}, weights: map[string]int{"a": 3}, expectedOrder: "abcabaaa"},
+		{options: Options{Scheduling: WeightedRoundRobin}, weights: map[string]int{"a": 3}, expectedOrder: "aaabcaab"},
+		{options: Options{Scheduling: DeficitRoundRobin, Quantum: 2}, weights: map[string]int{"b": 2}, expectedOrder: "aabbcaaa"},
+	}
+
+	for i, aCase := range cases {
+		fq := NewFairQueue(aCase.options)
+		for key, weight := range aCase.weights {
+			fq.SetWeight(key, weight)
+		}
+		fill(fq, "a", 5)
+		fill(fq, "b", 2)
+		fill(fq, "c", 1)
+
+		if order := dequeueKeys(fq); order != aCase.expectedOrder {
+			t.Errorf("Error in case %d. Expected order %s, got %s", i, aCase.expectedOrder, order)
+		}
+	}
+}
+
+func TestDeficitCosts(t *testing.T) {
+	fq := NewFairQueue(Options{Scheduling: DeficitRoundRobin, Quantum: 3, Cost: func(value interface{}) int {
+		return value.(int)
+	}})
+	//Key a sends large values, key b small ones. Key a has to save up credit for two turns before it is served.
+	for i := 0; i < 4; i++ {
+		fq.Enqueue("a", 5)
+	}
+	for i := 0; i < 12; i++ {
+		fq.Enqueue("b", 1)
+	}
+
+	served := map[string]int{}
+	for i := 0; i < 8; i++ {
+		key, value, err := fq.Dequeue()
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err.Error())
+		}
+		served[key] += value.(int)
+	}
+	if served["a"] != 5 || served["b"] != 7 {
+		t.Errorf("Expected costs 5 and 7 served, got %v", served)
+	}
+}
+
+func TestFifoWithinKey(t *testing.T) {
+	fq := NewFairQueue(Options{})
+	fill(fq, "a", 3)
+	fill(fq, "b", 3)
+
+	next := map[string]int{}
+	for fq.Length() > 0 {
+		key, value, _ := fq.Dequeue()
+		if value != next[key] {
+			t.Fatalf("Expected %d for key %s, got %v", next[key], key, value)
+		}
+		next[key]++
+	}
+}
+
+func TestCapsAndCleanup(t *testing.T) {
+	fq := NewFairQueue(Options{MaxPerKey: 2})
+	fq.SetCap("big", 3)
+
+	for i := 0; i < 3; i++ {
+		fq.Enqueue("small", i)
+		fq.Enqueue("big", i)
+	}
+	if err := fq.Enqueue("small", 3); err == nil {
+		t.Errorf("Expected an error when a key is over its cap")
+	}
+	if fq.KeyLength("small") != 2 || fq.KeyLength("big") != 3 || fq.Length() != 5 || fq.Keys() != 2 {
+		t.Errorf("Unexpected lengths %d, %d, %d and %d keys", fq.KeyLength("small"), fq.KeyLength("big"), fq.Length(), fq.Keys())
+	}
+
+	dequeueKeys(fq)
+	if fq.Keys() != 0 || fq.KeyLength("big") != 0 {
+		t.Errorf("Expected empty sub-queues to be removed, got %d keys", fq.Keys())
+	}
+	if _, _, err := fq.Dequeue(); err == nil {
+		t.Errorf("Expected an error on an empty queue")
+	}
+
+	//Caps survive the removal of a sub-queue
+	fill(fq, "big", 4)
+	if fq.KeyLength("big") != 3 {
+		t.Errorf("Expected cap 3 to still apply, got length %d", fq.KeyLength("big"))
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	fq := NewFairQueue(Options{Scheduling: WeightedRoundRobin})
+	wg := sync.WaitGroup{}
+	for producer := 0; producer < 4; producer++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			fill(fq, key, 250)
+		}(fmt.Sprintf("tenant-%d", producer))
+	}
+	wg.Wait()
+
+	counts := map[string]int{}
+	consumed := make(chan string, 1000)
+	for consumer := 0; consumer < 4; consumer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				key, _, err := fq.Dequeue()
+				if err != nil {
+					return
+				}
+				consumed <- key
+			}
+		}()
+	}
+	wg.Wait()
+	close(consumed)
+	for key := range consumed {
+		counts[key]++
+	}
+	if len(counts) != 4 || counts["tenant-0"] != 250 {
+		t.Errorf("Expected 250 values from each of 4 keys, got %v", counts)
+	}
+}
+
+func TestNil(t *testing.T) {
+	var fq *FairQueue
+	if fq.Length() != 0 || fq.KeyLength("a") != 0 || fq.Keys() != 0 {
+		t.Errorf("Expected zero lengths on a nil queue")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Enqueue to panic on a nil queue")
+		}
+	}()
+	fq.Enqueue("a", 1)
+}
@@ -0,0 +1,500 @@
+//History is an undo/redo manager built on two stack.Stack instances.
+//Commands are run by Do and recorded, Undo and Redo walk back and forth through them.
+//Commands can be grouped into transactions that are undone as one, the depth can be bounded,
+//savepoints remember a position to return to, and listeners are told about every change.
+//Safe to use concurrently, commands are run one at a time. A panicking command leaves the history usable.
+package history
+
+import (
+	"datatypes/stack"
+	"errors"
+	"sync"
+)
+
+//*************** History Public Interface ***************
+
+//Command is a reversible change.
+type Command interface {
+	Do() error
+	Undo() error
+}
+
+//NewCommand builds a Command from a pair of functions.
+func NewCommand(do func() error, undo func() error) Command {
+	return commandFuncs{do: do, undo: undo}
+}
+
+//ChangeKind tells what happened to the history.
+type ChangeKind int
+
+const (
+	Done ChangeKind = iota
+	Undone
+	Redone
+	//A transaction was committed as one entry
+	Committed
+	//A transaction was aborted and its commands undone
+	RolledBack
+	//The oldest entry was dropped to respect the maximum depth
+	Evicted
+	Cleared
+)
+
+//Change describes a change of the history, passed to listeners.
+type Change struct {
+	Kind ChangeKind
+	//Commands of the affected entry in the order they were done. Empty for Cleared.
+	Commands []Command
+	CanUndo  bool
+	CanRedo  bool
+}
+
+//Savepoint is a position in the history returned by Savepoint.
+type Savepoint struct {
+	entryId uint64
+}
+
+//History records commands for undo and redo. Goroutine safe.
+type History struct {
+	//Entries that can be undone, newest at the top, and entries that can be redone, next at the top.
+	//The undo stack is bounded to the maximum depth and drops its bottom entry when full.
+	undo *stack.Stack
+	redo *stack.Stack
+	//Open transactions, innermost at the top
+	transactions *stack.Stack
+	maxDepth     int
+	nextId       uint64
+	//Id of the newest evicted entry, the oldest position still reachable
+	floorId   uint64
+	listeners []func(Change)
+	mutex     sync.Mutex
+}
+
+//NewHistory initializes an empty History keeping at most maxDepth entries. Zero means no limit.
+//Recommended way of initialization.
+func NewHistory(maxDepth int) *History {
+	h := &History{redo: stack.NewStack(), transactions: stack.NewStack(), maxDepth: maxDepth}
+	h.undo = h.newUndoStack()
+	return h
+}
+
+//OnChange registers a listener. Listeners are called after every change, outside the lock, in the order they were added.
+//Panics on an uninitialized history.
+func (h *History) OnChange(listener func(Change)) {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+}
+
+//CanUndo reports whether there is an entry to undo. Returns false on an uninitialized history.
+func (h *History) CanUndo() bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.undo.Length() > 0
+}
+
+//CanRedo reports whether there is an entry to redo. Returns false on an uninitialized history.
+func (h *History) CanRedo() bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.redo.Length() > 0
+}
+
+//UndoDepth returns the number of entries that can be undone. A committed transaction counts as one.
+func (h *History) UndoDepth() int {
+	if h == nil {
+		return 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.undo.Length()
+}
+
+//RedoDepth returns the number of entries that can be redone.
+func (h *History) RedoDepth() int {
+	if h == nil {
+		return 0
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.redo.Length()
+}
+
+//Do runs the command and records it. Everything that could be redone is discarded.
+//Inside a transaction the command becomes part of it.
+//If the command fails, nothing is recorded and the error is returned.
+//Panics on an uninitialized history.
+func (h *History) Do(command Command) error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, err := h.locked(func() ([]Change, error) {
+		if err := command.Do(); err != nil {
+			return nil, err
+		}
+
+		if top, err := h.transactions.Peek(); err == nil {
+			openTransaction := top.(*entry)
+			openTransaction.commands = append(openTransaction.commands, command)
+			return nil, nil
+		}
+		return h.record(&entry{commands: []Command{command}}, Done), nil
+	})
+
+	h.notify(changes)
+	return err
+}
+
+//Undo reverts the newest entry. Commands of a transaction are undone newest first.
+//If there is nothing to undo, a transaction is open, or a command fails, returns an error.
+//A failed entry stays in place, commands of it that were already undone are done again.
+//Panics on an uninitialized history.
+func (h *History) Undo() error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, err := h.locked(h.undoEntry)
+
+	h.notify(changes)
+	return err
+}
+
+//Redo runs the entry undone last again.
+//If there is nothing to redo, a transaction is open, or a command fails, returns an error.
+//Panics on an uninitialized history.
+func (h *History) Redo() error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, err := h.locked(h.redoEntry)
+
+	h.notify(changes)
+	return err
+}
+
+//Begin opens a transaction. Commands done until the matching Commit are recorded as one entry.
+//Transactions can be nested, an inner transaction becomes part of the outer one.
+//Panics on an uninitialized history.
+func (h *History) Begin() {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.transactions.Push(&entry{})
+}
+
+//Commit closes the innermost transaction. An empty transaction leaves no entry.
+//If no transaction is open, returns an error.
+//Panics on an uninitialized history.
+func (h *History) Commit() error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, err := h.locked(func() ([]Change, error) {
+		top, err := h.transactions.Pop()
+		if err != nil {
+			return nil, errors.New("No transaction is open")
+		}
+		committed := top.(*entry)
+
+		if outer, err := h.transactions.Peek(); err == nil {
+			outerTransaction := outer.(*entry)
+			outerTransaction.commands = append(outerTransaction.commands, committed.commands...)
+			return nil, nil
+		}
+		if len(committed.commands) == 0 {
+			return nil, nil
+		}
+		return h.record(committed, Committed), nil
+	})
+
+	h.notify(changes)
+	return err
+}
+
+//Rollback closes the innermost transaction and undoes its commands, newest first.
+//If no transaction is open or a command fails to undo, returns an error. Undoing stops at the first failure.
+//Panics on an uninitialized history.
+func (h *History) Rollback() error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, err := h.locked(func() ([]Change, error) {
+		top, err := h.transactions.Pop()
+		if err != nil {
+			return nil, errors.New("No transaction is open")
+		}
+		aborted := top.(*entry)
+
+		for i := len(aborted.commands) - 1; i >= 0; i-- {
+			if err := aborted.commands[i].Undo(); err != nil {
+				return nil, err
+			}
+		}
+		return []Change{h.change(RolledBack, aborted)}, nil
+	})
+
+	h.notify(changes)
+	return err
+}
+
+//Savepoint returns the current position. Open transactions are not part of it.
+//Panics on an uninitialized history.
+func (h *History) Savepoint() Savepoint {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return Savepoint{entryId: h.positionId()}
+}
+
+//IsAt reports whether the history is at the savepoint, e.g. to tell if a document changed since it was saved.
+func (h *History) IsAt(savepoint Savepoint) bool {
+	if h == nil {
+		return false
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return h.positionId() == savepoint.entryId
+}
+
+//RestoreSavepoint undoes or redoes entries until the history is at the savepoint.
+//Returns an error if the savepoint can't be reached anymore - its entries were evicted or discarded by Do -
+//if a transaction is open, or if a command fails, in which case the history stays where it got to.
+//Panics on an uninitialized history.
+func (h *History) RestoreSavepoint(savepoint Savepoint) error {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes := []Change{}
+	_, err := h.locked(func() ([]Change, error) {
+		var step func() ([]Change, error)
+		switch {
+		case h.contains(h.undo, savepoint.entryId) || savepoint.entryId == h.floorId:
+			step = h.undoEntry
+		case h.contains(h.redo, savepoint.entryId):
+			step = h.redoEntry
+		default:
+			return nil, errors.New("Savepoint is not reachable")
+		}
+
+		for h.positionId() != savepoint.entryId {
+			stepChanges, err := step()
+			changes = append(changes, stepChanges...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+
+	h.notify(changes)
+	return err
+}
+
+//Clear forgets every entry without undoing anything. Open transactions are discarded too.
+//Panics on an uninitialized history.
+func (h *History) Clear() {
+	if h == nil {
+		panic("History is nil")
+	}
+
+	changes, _ := h.locked(func() ([]Change, error) {
+		h.floorId = h.positionId()
+		h.undo = h.newUndoStack()
+		h.redo = stack.NewStack()
+		h.transactions = stack.NewStack()
+		return []Change{h.change(Cleared, &entry{})}, nil
+	})
+
+	h.notify(changes)
+}
+
+//*************** History Internal Structure ***************
+
+type commandFuncs struct {
+	do   func() error
+	undo func() error
+}
+
+func (c commandFuncs) Do() error {
+	return c.do()
+}
+
+func (c commandFuncs) Undo() error {
+	return c.undo()
+}
+
+//entry is one step of the history, a single command or a committed transaction.
+type entry struct {
+	id       uint64
+	commands []Command
+}
+
+//locked runs work holding the lock. The lock is released even if a command panics.
+func (h *History) locked(work func() ([]Change, error)) ([]Change, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return work()
+}
+
+//newUndoStack returns an empty undo stack bounded to the maximum depth.
+func (h *History) newUndoStack() *stack.Stack {
+	undo := stack.NewStack()
+	if h.maxDepth > 0 {
+		undo.SetCapacity(h.maxDepth, stack.OverflowDiscardBottom)
+	}
+	return undo
+}
+
+//record pushes a new entry and discards the redo stack. At the maximum depth the oldest entry is evicted in O(1). No locking.
+func (h *History) record(newEntry *entry, kind ChangeKind) []Change {
+	h.nextId++
+	newEntry.id = h.nextId
+
+	var evicted *entry
+	if h.undo.IsFull() {
+		bottom, _ := h.undo.Bottom()
+		evicted = bottom.(*entry)
+		h.floorId = evicted.id
+	}
+	//A full undo stack drops its bottom entry to make room
+	h.undo.Push(newEntry)
+	h.redo = stack.NewStack()
+
+	changes := []Change{h.change(kind, newEntry)}
+	if evicted != nil {
+		changes = append(changes, h.change(Evicted, evicted))
+	}
+	return changes
+}
+
+//undoEntry moves the newest entry from the undo to the redo stack, undoing its commands. No locking.
+func (h *History) undoEntry() ([]Change, error) {
+	if h.transactions.Length() > 0 {
+		return nil, errors.New("Can't undo while a transaction is open")
+	}
+	top, err := h.undo.Peek()
+	if err != nil {
+		return nil, errors.New("Nothing to undo")
+	}
+	undone := top.(*entry)
+
+	for i := len(undone.commands) - 1; i >= 0; i-- {
+		if err := undone.commands[i].Undo(); err != nil {
+			//Put the entry back into a consistent state, best effort
+			for j := i + 1; j < len(undone.commands); j++ {
+				undone.commands[j].Do()
+			}
+			return nil, err
+		}
+	}
+
+	h.undo.Pop()
+	h.redo.Push(undone)
+	return []Change{h.change(Undone, undone)}, nil
+}
+
+//redoEntry moves the next entry from the redo to the undo stack, doing its commands again. No locking.
+func (h *History) redoEntry() ([]Change, error) {
+	if h.transactions.Length() > 0 {
+		return nil, errors.New("Can't redo while a transaction is open")
+	}
+	top, err := h.redo.Peek()
+	if err != nil {
+		return nil, errors.New("Nothing to redo")
+	}
+	redone := top.(*entry)
+
+	for i, command := range redone.commands {
+		if err := command.Do(); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				redone.commands[j].Undo()
+			}
+			return nil, err
+		}
+	}
+
+	h.redo.Pop()
+	h.undo.Push(redone)
+	return []Change{h.change(Redone, redone)}, nil
+}
+
+//positionId returns the id of the newest entry that can be undone, or the floor if there is none. No locking.
+func (h *History) positionId() uint64 {
+	if top, err := h.undo.Peek(); err == nil {
+		return top.(*entry).id
+	}
+	return h.floorId
+}
+
+//contains reports whether an entry with the id is on the stack. No locking.
+func (h *History) contains(entries *stack.Stack, id uint64) bool {
+	for _, value := range entries.Snapshot().Values() {
+		if value.(*entry).id == id {
+			return true
+		}
+	}
+	return false
+}
+
+//change describes the current state after a change to an entry. No locking.
+func (h *History) change(kind ChangeKind, changed *entry) Change {
+	return Change{
+		Kind:     kind,
+		Commands: append([]Command{}, changed.commands...),
+		CanUndo:  h.undo.Length() > 0,
+		CanRedo:  h.redo.Length() > 0,
+	}
+}
+
+//notify calls the listeners. Has to be called without holding the lock.
+func (h *History) notify(changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	h.mutex.Lock()
+	listeners := append([]func(Change){}, h.listeners...)
+	h.mutex.Unlock()
+
+	for _, change := range changes {
+		for _, listener := range listeners {
+			listener(change)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package history_test
+
+import (
+	. "datatypes/history"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+//document is a piece of text edited through commands.
+type document struct {
+	text string
+}
+
+func (d *document) appendText(suffix string) Command {
+	return NewCommand(func() error {
+		d.text += suffix
+		return nil
+	}, func() error {
+		d.text = strings.TrimSuffix(d.text, suffix)
+		return nil
+	})
+}
+
+func failing() Command {
+	return NewCommand(func() error { return errors.New("Can't do") }, func() error { return nil })
+}
+
+//*************** Public Interface Test ***************
+
+func TestUndoRedo(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+	if err := h.Undo(); err == nil {
+		t.Errorf("Expected an error when there is nothing to undo")
+	}
+
+	for _, word := range []string{"a", "b", "c"} {
+		h.Do(doc.appendText(word))
+	}
+	h.Undo()
+	h.Undo()
+	if doc.text != "a" || h.UndoDepth() != 1 || h.RedoDepth() != 2 {
+		t.Errorf("Expected a with depths 1 and 2, got %s with %d and %d", doc.text, h.UndoDepth(), h.RedoDepth())
+	}
+	h.Redo()
+	if doc.text != "ab" {
+		t.Errorf("Expected ab, got %s", doc.text)
+	}
+
+	//A new command discards what could be redone
+	h.Do(doc.appendText("x"))
+	if h.CanRedo() || h.Redo() == nil {
+		t.Errorf("Expected nothing to redo after a new command")
+	}
+	if err := h.Do(failing()); err == nil || h.UndoDepth() != 3 {
+		t.Errorf("Expected a failed command to be reported and not recorded")
+	}
+}
+
+func TestTransactions(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+
+	h.Begin()
+	h.Do(doc.appendText("a"))
+	h.Begin()
+	h.Do(doc.appendText("b"))
+	h.Commit()
+	if err := h.Undo(); err == nil {
+		t.Errorf("Expected an error when undoing inside a transaction")
+	}
+	h.Do(doc.appendText("c"))
+	h.Commit()
+
+	if doc.text != "abc" || h.UndoDepth() != 1 {
+		t.Fatalf("Expected abc as one entry, got %s with depth %d", doc.text, h.UndoDepth())
+	}
+	h.Undo()
+	if doc.text != "" {
+		t.Errorf("Expected the whole transaction undone, got %s", doc.text)
+	}
+	h.Redo()
+
+	h.Begin()
+	h.Do(doc.appendText("d"))
+	h.Do(doc.appendText("e"))
+	if err := h.Rollback(); err != nil || doc.text != "abc" || h.UndoDepth() != 1 {
+		t.Errorf("Expected rollback to abc, got %s with depth %d and error %v", doc.text, h.UndoDepth(), err)
+	}
+	if h.Commit() == nil || h.Rollback() == nil {
+		t.Errorf("Expected errors without an open transaction")
+	}
+
+	h.Begin()
+	h.Commit()
+	if h.UndoDepth() != 1 {
+		t.Errorf("Expected an empty transaction to leave no entry")
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	h := NewHistory(3)
+	doc := &document{}
+	evicted := 0
+	h.OnChange(func(change Change) {
+		if change.Kind == Evicted {
+			evicted++
+		}
+	})
+
+	for _, word := range []string{"a", "b", "c", "d", "e"} {
+		h.Do(doc.appendText(word))
+	}
+	if h.UndoDepth() != 3 || evicted != 2 {
+		t.Fatalf("Expected depth 3 after 2 evictions, got %d and %d", h.UndoDepth(), evicted)
+	}
+	for h.CanUndo() {
+		h.Undo()
+	}
+	if doc.text != "ab" {
+		t.Errorf("Expected the oldest two commands to stay done, got %s", doc.text)
+	}
+}
+
+func TestSavepoints(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+	empty := h.Savepoint()
+
+	h.Do(doc.appendText("a"))
+	h.Do(doc.appendText("b"))
+	saved := h.Savepoint()
+	h.Do(doc.appendText("c"))
+	if h.IsAt(saved) {
+		t.Errorf("Expected the history to have moved past the savepoint")
+	}
+
+	cases := []struct {
+		savepoint    Savepoint
+		expectedText string
+	}{
+		{savepoint: saved, expectedText: "ab"},
+		{savepoint: empty, expectedText: ""},
+		{savepoint: saved, expectedText: "ab"},
+	}
+	for i, aCase := range cases {
+		if err := h.RestoreSavepoint(aCase.savepoint); err != nil || doc.text != aCase.expectedText || !h.IsAt(aCase.savepoint) {
+			t.Errorf("Error in case %d. Expected %s, got %s with error %v", i, aCase.expectedText, doc.text, err)
+		}
+	}
+
+	//Undoing and doing something else makes the redo side unreachable
+	h.Undo()
+	h.Do(doc.appendText("z"))
+	if err := h.RestoreSavepoint(saved); err == nil {
+		t.Errorf("Expected an unreachable savepoint to be reported")
+	}
+}
+
+func TestUndoFailure(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+	broken := NewCommand(func() error { return nil }, func() error { return errors.New("Can't undo") })
+
+	h.Begin()
+	h.Do(broken)
+	h.Do(doc.appendText("a"))
+	h.Commit()
+
+	if err := h.Undo(); err == nil {
+		t.Errorf("Expected the undo failure to be reported")
+	}
+	if doc.text != "a" || h.UndoDepth() != 1 {
+		t.Errorf("Expected the entry to stay done, got %s with depth %d", doc.text, h.UndoDepth())
+	}
+}
+
+func TestPanickingCommand(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+	panicking := NewCommand(func() error { panic("Command bug") }, func() error { return nil })
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected the panic to reach the caller")
+			}
+		}()
+		h.Do(panicking)
+	}()
+
+	//The history must not stay locked
+	done := make(chan struct{})
+	go func() {
+		h.Do(doc.appendText("a"))
+		h.Undo()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("History stayed locked after a command panicked")
+	}
+	if doc.text != "" || !h.CanRedo() {
+		t.Errorf("Expected the command to be done and undone, got %s", doc.text)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	h := NewHistory(0)
+	doc := &document{}
+	kinds := []ChangeKind{}
+	h.OnChange(func(change Change) {
+		kinds = append(kinds, change.Kind)
+		//Listeners run outside the lock and can use the history
+		h.CanUndo()
+	})
+
+	h.Do(doc.appendText("a"))
+	h.Undo()
+	h.Redo()
+	h.Begin()
+	h.Do(doc.appendText("b"))
+	h.Commit()
+	h.Begin()
+	h.Rollback()
+	h.Clear()
+
+	expected := []ChangeKind{Done, Undone, Redone, Committed, RolledBack, Cleared}
+	if fmt.Sprint(kinds) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, kinds)
+	}
+	if h.CanUndo() || doc.text != "ab" {
+		t.Errorf("Expected Clear to forget entries without undoing them")
+	}
+}
+
+func ExampleHistory() {
+	h := NewHistory(100)
+	doc := &document{}
+
+	h.Do(doc.appendText("Hello"))
+	h.Do(doc.appendText(", world"))
+	h.Undo()
+	fmt.Println(doc.text)
+	h.Redo()
+	fmt.Println(doc.text)
+	//Output:
+	//Hello
+	//Hello, world
+}
@@ -0,0 +1,15 @@
+//Equality compares interface{} values the way the containers of this repository search for them.
+//Internal, the containers expose it through their own methods.
+package equality
+
+//*************** Comparison ***************
+
+//Equal compares two values with ==, reporting false instead of panicking for values that can't be compared.
+func Equal(first interface{}, second interface{}) (equal bool) {
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+	return first == second
+}
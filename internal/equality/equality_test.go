@@ -0,0 +1,26 @@
+package equality_test
+
+import (
+	. "datatypes/internal/equality"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestEqual(t *testing.T) {
+	cases := []struct {
+		first    interface{}
+		second   interface{}
+		expected bool
+	}{
+		{first: 1, second: 1, expected: true},
+		{first: 1, second: "1", expected: false},
+		{first: nil, second: nil, expected: true},
+		{first: []int{1}, second: []int{1}, expected: false},
+	}
+	for i, aCase := range cases {
+		if Equal(aCase.first, aCase.second) != aCase.expected {
+			t.Errorf("Error in case %d. Expected %v", i, aCase.expected)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//Serialize holds the encoding code shared by the containers of this repository:
+//decoding JSON arrays and writing, reading and copying snapshots.
+//Internal, the containers expose it through their own methods.
+package serialize
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+//*************** JSON ***************
+
+//DecodeJSONArray decodes a JSON array, passing every element to decodeElement.
+//Returns nothing unless every element was decoded.
+func DecodeJSONArray(data []byte, decodeElement func(json.RawMessage) (interface{}, error)) ([]interface{}, error) {
+	if decodeElement == nil {
+		return nil, errors.New("Element decoder is nil")
+	}
+
+	rawValues := []json.RawMessage{}
+	if err := json.Unmarshal(data, &rawValues); err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		value, err := decodeElement(rawValue)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+//DecodeInterface decodes an element the way encoding/json decodes into interface{}.
+func DecodeInterface(rawValue json.RawMessage) (interface{}, error) {
+	var value interface{}
+	err := json.Unmarshal(rawValue, &value)
+	return value, err
+}
@@ -0,0 +1,38 @@
+package serialize_test
+
+import (
+	. "datatypes/internal/serialize"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestDecodeJSONArray(t *testing.T) {
+	failOnTwo := func(rawValue json.RawMessage) (interface{}, error) {
+		if string(rawValue) == "2" {
+			return nil, errors.New("Can't decode 2")
+		}
+		return DecodeInterface(rawValue)
+	}
+
+	cases := []struct {
+		data           string
+		decodeElement  func(json.RawMessage) (interface{}, error)
+		expectedLength int
+		expectedErr    bool
+	}{
+		{data: `[1, "a", null]`, decodeElement: DecodeInterface, expectedLength: 3},
+		{data: `[]`, decodeElement: DecodeInterface, expectedLength: 0},
+		{data: `{}`, decodeElement: DecodeInterface, expectedErr: true},
+		{data: `[1, 2]`, decodeElement: failOnTwo, expectedErr: true},
+		{data: `[1]`, decodeElement: nil, expectedErr: true},
+	}
+	for i, aCase := range cases {
+		values, err := DecodeJSONArray([]byte(aCase.data), aCase.decodeElement)
+		if (err != nil) != aCase.expectedErr || len(values) != aCase.expectedLength {
+			t.Errorf("Error in case %d. Got %v with error %v", i, values, err)
+		}
+	}
+}
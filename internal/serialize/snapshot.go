@@ -0,0 +1,44 @@
+package serialize
+
+import (
+	"datatypes/binarycodec"
+	"encoding"
+	"io"
+)
+
+//*************** Snapshots ***************
+
+//Copy returns a copy of the values, never nil.
+func Copy(values []interface{}) []interface{} {
+	copied := make([]interface{}, len(values))
+	copy(copied, values)
+	return copied
+}
+
+//WriteTo writes the encoded snapshot to the writer, for io.WriterTo implementations.
+func WriteTo(writer io.Writer, snapshot encoding.BinaryMarshaler) (int64, error) {
+	data, err := snapshot.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	written, err := writer.Write(data)
+	return int64(written), err
+}
+
+//WriteFile atomically replaces the file at path with the encoded snapshot.
+func WriteFile(path string, snapshot encoding.BinaryMarshaler) error {
+	data, err := snapshot.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return binarycodec.WriteFile(path, data)
+}
+
+//Load reads everything up to EOF and decodes it into the container.
+func Load(reader io.Reader, container encoding.BinaryUnmarshaler) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return container.UnmarshalBinary(data)
+}
@@ -0,0 +1,17 @@
+package serialize_test
+
+import (
+	. "datatypes/internal/serialize"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestCopy(t *testing.T) {
+	original := []interface{}{1, 2}
+	copied := Copy(original)
+	copied[0] = 3
+	if original[0] != 1 || Copy(nil) == nil {
+		t.Errorf("Expected an independent, non-nil copy")
+	}
+}
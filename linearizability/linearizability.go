@@ -0,0 +1,276 @@
+//Linearizability is a test harness for the concurrent containers in this repository.
+//Records concurrent invocations together with call and return timestamps,
+//then checks that the recorded history is linearizable against a sequential model.
+//Uses the Wing-Gong algorithm with Lowe's memoization (the approach taken by Porcupine).
+//Intended to be driven from tests, preferably run with `go test -race`.
+package linearizability
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+//*************** Linearizability Public Interface ***************
+
+//Operation is a single completed invocation in a concurrent history.
+//Call and Return are timestamps; an operation happens before another if its Return is smaller than the other's Call.
+type Operation struct {
+	ClientId int
+	Input    interface{}
+	Output   interface{}
+	Call     int64
+	Return   int64
+}
+
+//Model is a sequential specification of a container.
+//Step applies an input to a state and reports whether the observed output is legal.
+//Step must not mutate the state it is given, it returns a new state instead.
+type Model struct {
+	Init func() interface{}
+	Step func(state interface{}, input interface{}, output interface{}) (legal bool, newState interface{})
+	//Equal compares two states. Optional, reflect.DeepEqual is used when nil.
+	Equal func(state1, state2 interface{}) bool
+}
+
+//Recorder collects operations from many goroutines. Goroutine safe.
+//Timestamps are taken from a shared logical clock, so every call and return gets a unique point in time.
+type Recorder struct {
+	clock      int64
+	operations []Operation
+	mutex      sync.Mutex
+}
+
+//NewRecorder initializes an empty Recorder. Recommended way of initialization.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+//Record invokes the operation and adds it to the history.
+//The input describes the invocation, the value returned by the operation is stored as its output.
+//Panics on an uninitialized Recorder.
+func (r *Recorder) Record(clientId int, input interface{}, operation func() interface{}) {
+	if r == nil {
+		panic("Recorder is nil")
+	}
+
+	call := atomic.AddInt64(&r.clock, 1)
+	output := operation()
+	ret := atomic.AddInt64(&r.clock, 1)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.operations = append(r.operations, Operation{ClientId: clientId, Input: input, Output: output, Call: call, Return: ret})
+}
+
+//History returns a copy of all the recorded operations. Returns nil on an uninitialized Recorder.
+func (r *Recorder) History() []Operation {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	history := make([]Operation, len(r.operations))
+	copy(history, r.operations)
+	return history
+}
+
+//CheckOperations returns true if the history is linearizable with respect to the model.
+//The check is exponential in the worst case, keep histories short (a few hundred operations).
+func CheckOperations(model Model, history []Operation) bool {
+	if model.Init == nil || model.Step == nil {
+		panic("Model must define Init and Step")
+	}
+	if model.Equal == nil {
+		model.Equal = reflect.DeepEqual
+	}
+	if len(history) == 0 {
+		return true
+	}
+
+	head := buildEntryList(history)
+	linearized := newBitset(len(history))
+	cache := map[uint64][]cacheEntry{}
+	calls := []callFrame{}
+	state := model.Init()
+
+	entry := head.next
+	for head.next != nil {
+		if entry == nil {
+			if panic_on_internal_inconsistencies {
+				panic("Walked past the end of the entry list")
+			}
+			return false
+		}
+
+		if entry.match != nil {
+			//Call entry - try to linearize the operation at this point
+			legal, newState := model.Step(state, entry.value, entry.match.value)
+			if legal {
+				newLinearized := linearized.clone().set(entry.id)
+				if !cacheContains(cache, model, newLinearized, newState) {
+					addToCache(cache, newLinearized, newState)
+					calls = append(calls, callFrame{entry: entry, state: state})
+					state = newState
+					linearized.set(entry.id)
+					entry.lift()
+					entry = head.next
+					continue
+				}
+			}
+			entry = entry.next
+			continue
+		}
+
+		//Return entry - the matching call could not be linearized, backtrack
+		if len(calls) == 0 {
+			return false
+		}
+		top := calls[len(calls)-1]
+		calls = calls[:len(calls)-1]
+		entry = top.entry
+		state = top.state
+		linearized.clear(entry.id)
+		entry.unlift()
+		entry = entry.next
+	}
+
+	return true
+}
+
+//*************** Linearizability Internal Structure ***************
+
+//Make runtime asserts fatal
+const (
+	panic_on_internal_inconsistencies = true
+)
+
+//entry is a call or a return event in a doubly linked list ordered by time.
+//Call entries point to their matching return entry.
+type entry struct {
+	id    int
+	value interface{}
+	time  int64
+	match *entry
+	prev  *entry
+	next  *entry
+}
+
+type callFrame struct {
+	entry *entry
+	state interface{}
+}
+
+type cacheEntry struct {
+	linearized bitset
+	state      interface{}
+}
+
+func buildEntryList(history []Operation) (head *entry) {
+	events := make([]*entry, 0, 2*len(history))
+	for id, op := range history {
+		returnEntry := &entry{id: id, value: op.Output, time: op.Return}
+		callEntry := &entry{id: id, value: op.Input, time: op.Call, match: returnEntry}
+		events = append(events, callEntry, returnEntry)
+	}
+
+	//Calls go before returns that share a timestamp, so such operations are treated as concurrent
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].time != events[j].time {
+			return events[i].time < events[j].time
+		}
+		return events[i].match != nil && events[j].match == nil
+	})
+
+	head = &entry{id: -1}
+	last := head
+	for _, event := range events {
+		last.next = event
+		event.prev = last
+		last = event
+	}
+	return head
+}
+
+//lift removes a call entry and its matching return entry from the list.
+func (e *entry) lift() {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	match := e.match
+	match.prev.next = match.next
+	if match.next != nil {
+		match.next.prev = match.prev
+	}
+}
+
+//unlift puts a lifted call entry and its matching return entry back into the list.
+func (e *entry) unlift() {
+	match := e.match
+	match.prev.next = match
+	if match.next != nil {
+		match.next.prev = match
+	}
+	e.prev.next = e
+	e.next.prev = e
+}
+
+func cacheContains(cache map[uint64][]cacheEntry, model Model, linearized bitset, state interface{}) bool {
+	for _, cached := range cache[linearized.hash()] {
+		if linearized.equals(cached.linearized) && model.Equal(state, cached.state) {
+			return true
+		}
+	}
+	return false
+}
+
+func addToCache(cache map[uint64][]cacheEntry, linearized bitset, state interface{}) {
+	hash := linearized.hash()
+	cache[hash] = append(cache[hash], cacheEntry{linearized: linearized, state: state})
+}
+
+type bitset []uint64
+
+func newBitset(bits int) bitset {
+	return make(bitset, (bits+63)/64)
+}
+
+func (b bitset) clone() bitset {
+	cloned := make(bitset, len(b))
+	copy(cloned, b)
+	return cloned
+}
+
+func (b bitset) set(position int) bitset {
+	b[position/64] |= 1 << uint(position%64)
+	return b
+}
+
+func (b bitset) clear(position int) bitset {
+	b[position/64] &^= 1 << uint(position%64)
+	return b
+}
+
+func (b bitset) equals(other bitset) bool {
+	if len(b) != len(other) {
+		return false
+	}
+	for i := range b {
+		if b[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//FNV-1a over the words of the bitset.
+func (b bitset) hash() uint64 {
+	hash := uint64(14695981039346656037)
+	for _, word := range b {
+		hash ^= word
+		hash *= 1099511628211
+	}
+	return hash
+}
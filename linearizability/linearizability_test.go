@@ -0,0 +1,147 @@
+package linearizability_test
+
+import (
+	. "datatypes/linearizability"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestCheckOperations(t *testing.T) {
+	cases := []struct {
+		model        Model
+		history      []Operation
+		linearizable bool
+	}{
+		//Empty history is trivially linearizable
+		{QueueModel, []Operation{}, true},
+		//Sequential FIFO history
+		{QueueModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 2}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Dequeue}, Output: Output{Value: 1}, Call: 5, Return: 6},
+		}, true},
+		//Sequential history returning values in LIFO order is not a queue
+		{QueueModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 2}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Dequeue}, Output: Output{Value: 2}, Call: 5, Return: 6},
+		}, false},
+		//Overlapping enqueues can be ordered either way
+		{QueueModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 1}, Output: Output{}, Call: 1, Return: 4},
+			{ClientId: 1, Input: Input{Op: Enqueue, Value: 2}, Output: Output{}, Call: 2, Return: 3},
+			{ClientId: 2, Input: Input{Op: Dequeue}, Output: Output{Value: 2}, Call: 5, Return: 6},
+		}, true},
+		//Dequeue on an empty queue may only fail before the enqueue takes effect
+		{QueueModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 1, Input: Input{Op: Dequeue}, Output: Output{Err: true}, Call: 3, Return: 4},
+		}, false},
+		{QueueModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Enqueue, Value: 1}, Output: Output{}, Call: 1, Return: 4},
+			{ClientId: 1, Input: Input{Op: Dequeue}, Output: Output{Err: true}, Call: 2, Return: 3},
+			{ClientId: 1, Input: Input{Op: Length}, Output: Output{Value: 1}, Call: 5, Return: 6},
+		}, true},
+		//Stack histories
+		{StackModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Push, Value: "a"}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: Push, Value: "b"}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Peek}, Output: Output{Value: "b"}, Call: 5, Return: 6},
+			{ClientId: 1, Input: Input{Op: Pop}, Output: Output{Value: "b"}, Call: 7, Return: 8},
+		}, true},
+		{StackModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Push, Value: "a"}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: Push, Value: "b"}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Pop}, Output: Output{Value: "a"}, Call: 5, Return: 6},
+		}, false},
+		//LinkedList histories
+		{ListModel, []Operation{
+			{ClientId: 0, Input: Input{Op: Append, Value: 0}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: InsertBefore, Index: 0, Value: 1}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: InsertAfter, Index: 1, Value: 2}, Output: Output{}, Call: 5, Return: 6},
+			{ClientId: 1, Input: Input{Op: GetValue, Index: 2}, Output: Output{Value: 2}, Call: 7, Return: 8},
+			{ClientId: 1, Input: Input{Op: Remove, Index: 3}, Output: Output{Err: true}, Call: 9, Return: 10},
+		}, true},
+		{ListModel, []Operation{
+			{ClientId: 0, Input: Input{Op: InsertBefore, Index: 1, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+		}, false},
+		//Deque histories
+		{DequeModel, []Operation{
+			{ClientId: 0, Input: Input{Op: PushBottom, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: PushBottom, Value: 2}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Steal}, Output: Output{Value: 1}, Call: 5, Return: 8},
+			{ClientId: 0, Input: Input{Op: PopBottom}, Output: Output{Value: 2}, Call: 6, Return: 7},
+			{ClientId: 0, Input: Input{Op: PopBottom}, Output: Output{Err: true}, Call: 9, Return: 10},
+		}, true},
+		{DequeModel, []Operation{
+			{ClientId: 0, Input: Input{Op: PushBottom, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 0, Input: Input{Op: PushBottom, Value: 2}, Output: Output{}, Call: 3, Return: 4},
+			{ClientId: 1, Input: Input{Op: Steal}, Output: Output{Value: 2}, Call: 5, Return: 6},
+		}, false},
+		//The last value can go to the owner or the thief, not both
+		{DequeModel, []Operation{
+			{ClientId: 0, Input: Input{Op: PushBottom, Value: 1}, Output: Output{}, Call: 1, Return: 2},
+			{ClientId: 1, Input: Input{Op: Steal}, Output: Output{Value: 1}, Call: 3, Return: 6},
+			{ClientId: 0, Input: Input{Op: PopBottom}, Output: Output{Value: 1}, Call: 4, Return: 5},
+		}, false},
+	}
+
+	for i, aCase := range cases {
+		linearizable := CheckOperations(aCase.model, aCase.history)
+		if linearizable != aCase.linearizable {
+			t.Errorf("Error in case %d. Expected linearizable %v, got %v", i, aCase.linearizable, linearizable)
+		}
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var nilRecorder *Recorder
+	if nilRecorder.History() != nil {
+		t.Errorf("History of a nil recorder should be nil")
+	}
+
+	recorder := NewRecorder()
+	recorder.Record(0, "first", func() interface{} { return 1 })
+	recorder.Record(1, "second", func() interface{} { return 2 })
+
+	history := recorder.History()
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 recorded operations, got %d", len(history))
+	}
+	if history[0].Input != "first" || history[0].Output != 1 || history[1].ClientId != 1 {
+		t.Errorf("Recorded operations are incorrect: %v", history)
+	}
+	if history[0].Call >= history[0].Return || history[0].Return >= history[1].Call {
+		t.Errorf("Recorded timestamps are out of order: %v", history)
+	}
+
+	//Test Record on a nil recorder panics
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Record on a nil recorder should cause a panic, did not")
+		}
+	}()
+	nilRecorder.Record(0, nil, func() interface{} { return nil })
+}
+
+func Example() {
+	recorder := NewRecorder()
+	var wg sync.WaitGroup
+
+	for client := 0; client < 2; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			recorder.Record(client, Input{Op: Length}, func() interface{} {
+				return Output{Value: 0}
+			})
+		}(client)
+	}
+	wg.Wait()
+
+	fmt.Println(CheckOperations(QueueModel, recorder.History()))
+	//Output: true
+}
@@ -0,0 +1,300 @@
+package linearizability
+
+import (
+	"reflect"
+)
+
+//*************** Container Models ***************
+
+//Op identifies the container method an Input describes.
+type Op int
+
+const (
+	Enqueue Op = iota
+	Dequeue
+	Push
+	Pop
+	Peek
+	Length
+	Append
+	Remove
+	GetValue
+	InsertBefore
+	InsertAfter
+	PushBottom
+	PopBottom
+	Steal
+)
+
+//Input describes a single container method invocation. Index is only used by LinkedList operations.
+type Input struct {
+	Op    Op
+	Index int
+	Value interface{}
+}
+
+//Output describes the result of a container method invocation.
+//Value holds the returned value, or the length for Length operations. Err is set if the method returned an error.
+type Output struct {
+	Value interface{}
+	Err   bool
+}
+
+//Queue is the method set of queue.Queue checked by QueueModel.
+type Queue interface {
+	Length() int
+	Peek() (interface{}, error)
+	Enqueue(value interface{})
+	Dequeue() (interface{}, error)
+}
+
+//Stack is the method set of stack.Stack checked by StackModel.
+type Stack interface {
+	Length() int
+	Peek() (interface{}, error)
+	Push(value interface{})
+	Pop() (interface{}, error)
+}
+
+//List is the method set of linkedlist.LinkedList checked by ListModel.
+type List interface {
+	Length() int
+	GetValue(index int) (interface{}, error)
+	Append(value interface{})
+	Remove(index int) (interface{}, error)
+	InsertBefore(index int, value interface{}) error
+	InsertAfter(index int, value interface{}) error
+}
+
+//Deque is the method set of a work-stealing deque checked by DequeModel.
+//Steal may only fail on an empty deque, wrap implementations that give up on contention so they retry.
+type Deque interface {
+	PushBottom(value interface{})
+	PopBottom() (interface{}, error)
+	Steal() (interface{}, error)
+}
+
+//QueueModel is the sequential specification of a FIFO queue. State is a slice, front first.
+var QueueModel = Model{
+	Init: func() interface{} { return []interface{}{} },
+	Step: func(state interface{}, input interface{}, output interface{}) (bool, interface{}) {
+		values := state.([]interface{})
+		in := input.(Input)
+		out := output.(Output)
+
+		switch in.Op {
+		case Enqueue:
+			return true, appendValue(values, in.Value)
+		case Dequeue:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[0]), values[1:]
+		case Peek:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[0]), values
+		case Length:
+			return out.Value == len(values), values
+		}
+		return false, values
+	},
+}
+
+//StackModel is the sequential specification of a LIFO stack. State is a slice, bottom first.
+var StackModel = Model{
+	Init: func() interface{} { return []interface{}{} },
+	Step: func(state interface{}, input interface{}, output interface{}) (bool, interface{}) {
+		values := state.([]interface{})
+		in := input.(Input)
+		out := output.(Output)
+
+		switch in.Op {
+		case Push:
+			return true, appendValue(values, in.Value)
+		case Pop:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[len(values)-1]), values[:len(values)-1]
+		case Peek:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[len(values)-1]), values
+		case Length:
+			return out.Value == len(values), values
+		}
+		return false, values
+	},
+}
+
+//ListModel is the sequential specification of a zero indexed list. State is a slice in list order.
+var ListModel = Model{
+	Init: func() interface{} { return []interface{}{} },
+	Step: func(state interface{}, input interface{}, output interface{}) (bool, interface{}) {
+		values := state.([]interface{})
+		in := input.(Input)
+		out := output.(Output)
+
+		switch in.Op {
+		case Append:
+			return true, insertValue(values, len(values), in.Value)
+		case InsertBefore:
+			if in.Index < 0 || in.Index > len(values) {
+				return out.Err, values
+			}
+			return !out.Err, insertValue(values, in.Index, in.Value)
+		case InsertAfter:
+			if in.Index < -1 || in.Index >= len(values) {
+				return out.Err, values
+			}
+			return !out.Err, insertValue(values, in.Index+1, in.Value)
+		case Remove:
+			if in.Index < 0 || in.Index >= len(values) {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[in.Index]), removeValue(values, in.Index)
+		case GetValue:
+			if in.Index < 0 || in.Index >= len(values) {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[in.Index]), values
+		case Length:
+			return out.Value == len(values), values
+		}
+		return false, values
+	},
+}
+
+//DequeModel is the sequential specification of a work-stealing deque. State is a slice, top first.
+//The owner pushes and pops at the bottom, Steal takes from the top.
+var DequeModel = Model{
+	Init: func() interface{} { return []interface{}{} },
+	Step: func(state interface{}, input interface{}, output interface{}) (bool, interface{}) {
+		values := state.([]interface{})
+		in := input.(Input)
+		out := output.(Output)
+
+		switch in.Op {
+		case PushBottom:
+			return true, appendValue(values, in.Value)
+		case PopBottom:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[len(values)-1]), values[:len(values)-1]
+		case Steal:
+			if len(values) == 0 {
+				return out.Err, values
+			}
+			return !out.Err && valuesEqual(out.Value, values[0]), values[1:]
+		}
+		return false, values
+	},
+}
+
+//*************** Recording Helpers ***************
+
+//RecordQueue invokes the operation described by input on the queue and records it.
+func RecordQueue(r *Recorder, clientId int, q Queue, input Input) {
+	r.Record(clientId, input, func() interface{} {
+		switch input.Op {
+		case Enqueue:
+			q.Enqueue(input.Value)
+			return Output{}
+		case Dequeue:
+			return newOutput(q.Dequeue())
+		case Peek:
+			return newOutput(q.Peek())
+		case Length:
+			return Output{Value: q.Length()}
+		}
+		panic("Operation is not supported by a queue")
+	})
+}
+
+//RecordStack invokes the operation described by input on the stack and records it.
+func RecordStack(r *Recorder, clientId int, s Stack, input Input) {
+	r.Record(clientId, input, func() interface{} {
+		switch input.Op {
+		case Push:
+			s.Push(input.Value)
+			return Output{}
+		case Pop:
+			return newOutput(s.Pop())
+		case Peek:
+			return newOutput(s.Peek())
+		case Length:
+			return Output{Value: s.Length()}
+		}
+		panic("Operation is not supported by a stack")
+	})
+}
+
+//RecordList invokes the operation described by input on the list and records it.
+func RecordList(r *Recorder, clientId int, l List, input Input) {
+	r.Record(clientId, input, func() interface{} {
+		switch input.Op {
+		case Append:
+			l.Append(input.Value)
+			return Output{}
+		case InsertBefore:
+			return Output{Err: l.InsertBefore(input.Index, input.Value) != nil}
+		case InsertAfter:
+			return Output{Err: l.InsertAfter(input.Index, input.Value) != nil}
+		case Remove:
+			return newOutput(l.Remove(input.Index))
+		case GetValue:
+			return newOutput(l.GetValue(input.Index))
+		case Length:
+			return Output{Value: l.Length()}
+		}
+		panic("Operation is not supported by a list")
+	})
+}
+
+//RecordDeque invokes the operation described by input on the deque and records it.
+func RecordDeque(r *Recorder, clientId int, d Deque, input Input) {
+	r.Record(clientId, input, func() interface{} {
+		switch input.Op {
+		case PushBottom:
+			d.PushBottom(input.Value)
+			return Output{}
+		case PopBottom:
+			return newOutput(d.PopBottom())
+		case Steal:
+			return newOutput(d.Steal())
+		}
+		panic("Operation is not supported by a deque")
+	})
+}
+
+//*************** Models Internal Structure ***************
+
+func newOutput(value interface{}, err error) Output {
+	return Output{Value: value, Err: err != nil}
+}
+
+func valuesEqual(value1, value2 interface{}) bool {
+	return reflect.DeepEqual(value1, value2)
+}
+
+//Model states are shared between search branches, so every change copies the slice.
+func appendValue(values []interface{}, value interface{}) []interface{} {
+	return insertValue(values, len(values), value)
+}
+
+func insertValue(values []interface{}, index int, value interface{}) []interface{} {
+	newValues := make([]interface{}, 0, len(values)+1)
+	newValues = append(newValues, values[:index]...)
+	newValues = append(newValues, value)
+	return append(newValues, values[index:]...)
+}
+
+func removeValue(values []interface{}, index int) []interface{} {
+	newValues := make([]interface{}, 0, len(values))
+	newValues = append(newValues, values[:index]...)
+	return append(newValues, values[index+1:]...)
+}
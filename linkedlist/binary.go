@@ -0,0 +1,62 @@
+package linkedlist
+
+import (
+	"datatypes/binarycodec"
+	"errors"
+)
+
+//*************** Binary Encoding ***************
+
+//MarshalBinary encodes the list in the binarycodec format, elements are encoded with gob.
+//Values are collected under a read lock, so the encoding is a consistent view.
+func (ll *LinkedList) MarshalBinary() ([]byte, error) {
+	return ll.MarshalBinaryWith(binarycodec.GobCodec{})
+}
+
+//UnmarshalBinary replaces the content of the list with values encoded by MarshalBinary.
+func (ll *LinkedList) UnmarshalBinary(data []byte) error {
+	return ll.UnmarshalBinaryWith(data, binarycodec.GobCodec{})
+}
+
+//GobEncode implements gob.GobEncoder, so the list can be a part of gob encoded structures.
+func (ll *LinkedList) GobEncode() ([]byte, error) {
+	return ll.MarshalBinary()
+}
+
+//GobDecode implements gob.GobDecoder.
+func (ll *LinkedList) GobDecode(data []byte) error {
+	return ll.UnmarshalBinary(data)
+}
+
+//MarshalBinaryWith encodes the list in the binarycodec format, elements are encoded with the codec.
+//Returns an error on an uninitialized LinkedList.
+func (ll *LinkedList) MarshalBinaryWith(codec binarycodec.Codec) ([]byte, error) {
+	if ll == nil {
+		return nil, errors.New("Linked list is nil")
+	}
+
+	ll.rwMutex.RLock()
+	values := ll.values()
+	ll.rwMutex.RUnlock()
+
+	return binarycodec.Encode(binarycodec.KindLinkedList, values, codec)
+}
+
+//UnmarshalBinaryWith replaces the content of the list with values decoded by the codec.
+//The list is left unchanged if decoding fails. Returns an error on an uninitialized LinkedList.
+func (ll *LinkedList) UnmarshalBinaryWith(data []byte, codec binarycodec.Codec) error {
+	if ll == nil {
+		return errors.New("Linked list is nil")
+	}
+
+	values, err := binarycodec.Decode(binarycodec.KindLinkedList, data, codec)
+	if err != nil {
+		return err
+	}
+
+	ll.rwMutex.Lock()
+	defer ll.rwMutex.Unlock()
+
+	ll.replaceValues(values)
+	return nil
+}
@@ -0,0 +1,70 @@
+package linkedlist_test
+
+import (
+	"bytes"
+	. "datatypes/linkedlist"
+	"encoding/gob"
+	"testing"
+)
+
+//*************** Binary Encoding Test ***************
+
+func TestBinaryRoundTrip(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		list           *LinkedList
+		expectedValues []interface{}
+	}{
+		{emptyList, []interface{}{}},
+		{twoElementList, []interface{}{0, 1}},
+		{tenElementList, []interface{}{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}},
+	}
+
+	for i, aCase := range cases {
+		data, err := aCase.list.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+
+		decodedList := NewLinkedList()
+		if err := decodedList.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if length := decodedList.Length(); length != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedValues), length)
+			continue
+		}
+		for index, expectedValue := range aCase.expectedValues {
+			value, _ := decodedList.GetValue(index)
+			if value != expectedValue {
+				t.Errorf("Error in case %d, index %d. Expected value %v, got %v", i, index, expectedValue, value)
+			}
+		}
+	}
+
+	//Corrupted data leaves the list unchanged
+	if err := oneElementList.UnmarshalBinary([]byte("DTL")); err == nil {
+		t.Errorf("Expected an error decoding corrupted data, got no error")
+	}
+	if oneElementList.Length() != 1 {
+		t.Errorf("List changed after a failed decoding")
+	}
+}
+
+func TestGob(t *testing.T) {
+	original := NewLinkedList()
+	original.Append(1)
+	original.Append("two")
+
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(original); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	decoded := NewLinkedList()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := decoded.GetValue(1); value != "two" {
+		t.Errorf("Expected value two, got %v", value)
+	}
+}
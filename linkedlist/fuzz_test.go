@@ -0,0 +1,106 @@
+package linkedlist
+
+import (
+	"testing"
+)
+
+//*************** Fuzz Test ***************
+
+const (
+	fuzzAppend = iota
+	fuzzInsertBefore
+	fuzzInsertAfter
+	fuzzRemove
+	fuzzGetValue
+	fuzzLength
+	numberOfFuzzOperations
+)
+
+//FuzzLinkedList decodes the input into a sequence of operations, two bytes per operation.
+//The first byte selects the method, the second is a signed index, so boundary indexes are reached quickly.
+//Every step is compared against a slice oracle and followed by an invariant check.
+func FuzzLinkedList(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{fuzzAppend, 0, fuzzGetValue, 0, fuzzRemove, 0})
+	f.Add([]byte{fuzzInsertBefore, 0, fuzzInsertBefore, 1, fuzzInsertBefore, 3, fuzzInsertAfter, 0xff, fuzzInsertAfter, 2})
+	f.Add([]byte{fuzzRemove, 0xff, fuzzGetValue, 0xff, fuzzInsertAfter, 0xfe, fuzzInsertBefore, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ll := NewLinkedList()
+		oracle := []interface{}{}
+
+		for step := 0; step+1 < len(data); step += 2 {
+			operation := int(data[step]) % numberOfFuzzOperations
+			index := int(int8(data[step+1]))
+			value := step
+
+			switch operation {
+			case fuzzAppend:
+				ll.Append(value)
+				oracle = append(oracle, value)
+			case fuzzInsertBefore:
+				err := ll.InsertBefore(index, value)
+				inBounds := index >= 0 && index <= len(oracle)
+				if inBounds == (err != nil) {
+					t.Fatalf("Step %d. InsertBefore(%d) on length %d returned error: %v", step, index, len(oracle), err)
+				}
+				if inBounds {
+					oracle = insertIntoOracle(oracle, index, value)
+				}
+			case fuzzInsertAfter:
+				err := ll.InsertAfter(index, value)
+				inBounds := index >= -1 && index < len(oracle)
+				if inBounds == (err != nil) {
+					t.Fatalf("Step %d. InsertAfter(%d) on length %d returned error: %v", step, index, len(oracle), err)
+				}
+				if inBounds {
+					oracle = insertIntoOracle(oracle, index+1, value)
+				}
+			case fuzzRemove:
+				removedValue, err := ll.Remove(index)
+				inBounds := index >= 0 && index < len(oracle)
+				if inBounds == (err != nil) {
+					t.Fatalf("Step %d. Remove(%d) on length %d returned error: %v", step, index, len(oracle), err)
+				}
+				if inBounds {
+					if removedValue != oracle[index] {
+						t.Fatalf("Step %d. Remove(%d) returned %v, expected %v", step, index, removedValue, oracle[index])
+					}
+					oracle = append(oracle[:index], oracle[index+1:]...)
+				}
+			case fuzzGetValue:
+				gotValue, err := ll.GetValue(index)
+				inBounds := index >= 0 && index < len(oracle)
+				if inBounds == (err != nil) {
+					t.Fatalf("Step %d. GetValue(%d) on length %d returned error: %v", step, index, len(oracle), err)
+				}
+				if inBounds && gotValue != oracle[index] {
+					t.Fatalf("Step %d. GetValue(%d) returned %v, expected %v", step, index, gotValue, oracle[index])
+				}
+			case fuzzLength:
+			}
+
+			if length := ll.Length(); length != len(oracle) {
+				t.Fatalf("Step %d. Expected length %d, got %d", step, len(oracle), length)
+			}
+			if err := ll.checkInvariants(); err != nil {
+				t.Fatalf("Step %d. Invariant violated: %s", step, err.Error())
+			}
+		}
+
+		//Walk the full list and compare it with the oracle
+		for i, expectedValue := range oracle {
+			gotValue, err := ll.GetValue(i)
+			if err != nil || gotValue != expectedValue {
+				t.Fatalf("Index %d. Expected value %v, got %v (error: %v)", i, expectedValue, gotValue, err)
+			}
+		}
+	})
+}
+
+func insertIntoOracle(oracle []interface{}, index int, value interface{}) []interface{} {
+	oracle = append(oracle, nil)
+	copy(oracle[index+1:], oracle[index:])
+	oracle[index] = value
+	return oracle
+}
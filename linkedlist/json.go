@@ -0,0 +1,50 @@
+package linkedlist
+
+import (
+	"datatypes/internal/serialize"
+	"encoding/json"
+	"errors"
+)
+
+//*************** JSON Encoding ***************
+
+//MarshalJSON encodes the linked list as a JSON array, in list order.
+//Values are collected under a read lock, so the array is a consistent view. Returns null on an uninitialized LinkedList.
+func (ll *LinkedList) MarshalJSON() ([]byte, error) {
+	if ll == nil {
+		return []byte("null"), nil
+	}
+
+	ll.rwMutex.RLock()
+	values := ll.values()
+	ll.rwMutex.RUnlock()
+
+	return json.Marshal(values)
+}
+
+//UnmarshalJSON replaces the content of the linked list with the values of a JSON array, in list order.
+//Values are decoded the way encoding/json decodes into interface{}, use UnmarshalJSONWith for typed values.
+//Returns an error on an uninitialized LinkedList.
+func (ll *LinkedList) UnmarshalJSON(data []byte) error {
+	return ll.UnmarshalJSONWith(data, serialize.DecodeInterface)
+}
+
+//UnmarshalJSONWith replaces the content of the linked list with the values of a JSON array, in list order.
+//Every array element is passed to decodeElement, which returns the value to store.
+//The linked list is left unchanged if decoding fails. Returns an error on an uninitialized LinkedList.
+func (ll *LinkedList) UnmarshalJSONWith(data []byte, decodeElement func(json.RawMessage) (interface{}, error)) error {
+	if ll == nil {
+		return errors.New("Linked list is nil")
+	}
+
+	values, err := serialize.DecodeJSONArray(data, decodeElement)
+	if err != nil {
+		return err
+	}
+
+	ll.rwMutex.Lock()
+	defer ll.rwMutex.Unlock()
+
+	ll.replaceValues(values)
+	return nil
+}
@@ -0,0 +1,99 @@
+package linkedlist_test
+
+import (
+	. "datatypes/linkedlist"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+//*************** JSON Encoding Test ***************
+
+func TestMarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		list         *LinkedList
+		expectedJSON string
+	}{
+		{nilList, "null"},
+		{emptyList, "[]"},
+		{oneElementList, "[0]"},
+		{twoElementList, "[0,1]"},
+		{tenElementList, `["0","1","2","3","4","5","6","7","8","9"]`},
+	}
+
+	for i, aCase := range cases {
+		data, err := json.Marshal(aCase.list)
+		if err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if string(data) != aCase.expectedJSON {
+			t.Errorf("Error in case %d. Expected JSON %s, got %s", i, aCase.expectedJSON, string(data))
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		list           *LinkedList
+		data           string
+		expectError    bool
+		expectedValues []interface{}
+	}{
+		{nilList, "[]", true, []interface{}{}},
+		{emptyList, `["a",null,"c"]`, false, []interface{}{"a", nil, "c"}},
+		//Existing content is replaced
+		{tenElementList, "[]", false, []interface{}{}},
+		//Invalid JSON leaves the list unchanged
+		{twoElementList, `"not an array"`, true, []interface{}{0, 1}},
+	}
+
+	for i, aCase := range cases {
+		err := json.Unmarshal([]byte(aCase.data), aCase.list)
+		if aCase.expectError && err == nil {
+			t.Errorf("Error in case %d. Expected an error, got no error", i)
+		}
+		if !aCase.expectError && err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if aCase.list == nil {
+			continue
+		}
+
+		if length := aCase.list.Length(); length != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedValues), length)
+			continue
+		}
+		for index, expectedValue := range aCase.expectedValues {
+			value, _ := aCase.list.GetValue(index)
+			if value != expectedValue {
+				t.Errorf("Error in case %d, index %d. Expected value %v, got %v", i, index, expectedValue, value)
+			}
+		}
+	}
+}
+
+func TestUnmarshalJSONWith(t *testing.T) {
+	linkedL := NewLinkedList()
+	decodeInt := func(raw json.RawMessage) (interface{}, error) {
+		return strconv.Atoi(string(raw))
+	}
+
+	err := linkedL.UnmarshalJSONWith([]byte("[5,6,7]"), decodeInt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	for index, expectedValue := range []int{5, 6, 7} {
+		value, _ := linkedL.GetValue(index)
+		if value != expectedValue {
+			t.Errorf("Index %d. Expected typed value %d, got %v", index, expectedValue, value)
+		}
+	}
+
+	//New values can be appended after decoding
+	linkedL.Append(8)
+	if value, _ := linkedL.GetValue(3); value != 8 {
+		t.Errorf("Expected appended value 8, got %v", value)
+	}
+}
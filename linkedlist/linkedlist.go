@@ -241,6 +241,50 @@ func (ll *LinkedList) insertElementBefore(index int, insertedElement *element) {
 	ll.changeLength(1)
 }
 
+//values returns all values in list order. No locking.
+func (ll *LinkedList) values() []interface{} {
+	values := make([]interface{}, 0, ll.length)
+	for currentElement := ll.baseElement; currentElement != nil; currentElement = currentElement.next {
+		values = append(values, currentElement.value)
+	}
+	return values
+}
+
+//replaceValues discards the current content and builds a new chain from values. No locking.
+func (ll *LinkedList) replaceValues(values []interface{}) {
+	ll.baseElement = nil
+	ll.length = 0
+	var lastElement *element
+	for _, value := range values {
+		newElem := newElement(value)
+		if lastElement == nil {
+			ll.setBaseElement(newElem)
+		} else {
+			lastElement.setNextElement(newElem)
+		}
+		lastElement = newElem
+		ll.changeLength(1)
+	}
+}
+
+//checkInvariants walks the whole list and verifies the internal structure. No locking.
+func (ll *LinkedList) checkInvariants() error {
+	if ll.length < 0 {
+		return errors.New("Length is negative")
+	}
+	count := 0
+	for currentElement := ll.baseElement; currentElement != nil; currentElement = currentElement.next {
+		count++
+		if count > ll.length {
+			return errors.New("More elements in the chain than the recorded length")
+		}
+	}
+	if count != ll.length {
+		return errors.New("Fewer elements in the chain than the recorded length")
+	}
+	return nil
+}
+
 func (ll *LinkedList) setBaseElement(newBaseELement *element) {
 	ll.baseElement = newBaseELement
 }
@@ -1,6 +1,7 @@
 package linkedlist_test
 
 import (
+	"datatypes/linearizability"
 	. "datatypes/linkedlist"
 	"fmt"
 	"sync"
@@ -441,3 +442,138 @@ func bombardLinkedList(linkedL *LinkedList, waitGroup *sync.WaitGroup) {
 
 	waitGroup.Done()
 }
+
+//TestLinearizability records concurrent operations on a LinkedList and checks them against a sequential list model.
+func TestLinearizability(t *testing.T) {
+	linkedL := NewLinkedList()
+	recorder := linearizability.NewRecorder()
+	var waitGroup sync.WaitGroup
+
+	for client := 0; client < 4; client++ {
+		waitGroup.Add(1)
+		go func(client int) {
+			defer waitGroup.Done()
+			for i := 0; i < 15; i++ {
+				value := fmt.Sprintf("%d-%d", client, i)
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.InsertBefore, Index: 0, Value: value})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.Append, Value: value})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.InsertAfter, Index: 1, Value: value})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.GetValue, Index: 1})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.Remove, Index: 2})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.Remove, Index: 0})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.Remove, Index: 0})
+				linearizability.RecordList(recorder, client, linkedL, linearizability.Input{Op: linearizability.Length})
+			}
+		}(client)
+	}
+	waitGroup.Wait()
+
+	if !linearizability.CheckOperations(linearizability.ListModel, recorder.History()) {
+		t.Errorf("Concurrent LinkedList history is not linearizable")
+	}
+}
+
+//*************** Benchmarks ***************
+
+//List sizes used to show the cost of walking the element chain.
+var benchmarkListLengths = []int{10, 1000, 100000}
+
+func newListOfLength(length int) *LinkedList {
+	linkedL := NewLinkedList()
+	for i := 0; i < length; i++ {
+		linkedL.InsertBefore(0, i)
+	}
+	return linkedL
+}
+
+//BenchmarkAppend shows the O(n) walk to the end of the list.
+func BenchmarkAppend(b *testing.B) {
+	for _, length := range benchmarkListLengths {
+		b.Run(fmt.Sprintf("length=%d", length), func(b *testing.B) {
+			linkedL := newListOfLength(length)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				linkedL.Append(i)
+				linkedL.Remove(length)
+			}
+		})
+	}
+}
+
+func BenchmarkInsertBeforeFront(b *testing.B) {
+	linkedL := NewLinkedList()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linkedL.InsertBefore(0, i)
+	}
+}
+
+func BenchmarkInsertAfter(b *testing.B) {
+	for _, length := range benchmarkListLengths {
+		b.Run(fmt.Sprintf("length=%d", length), func(b *testing.B) {
+			linkedL := newListOfLength(length)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				linkedL.InsertAfter(length/2, i)
+				linkedL.Remove(length/2 + 1)
+			}
+		})
+	}
+}
+
+func BenchmarkRemoveFront(b *testing.B) {
+	linkedL := newListOfLength(b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linkedL.Remove(0)
+	}
+}
+
+func BenchmarkGetValue(b *testing.B) {
+	for _, length := range benchmarkListLengths {
+		b.Run(fmt.Sprintf("length=%d", length), func(b *testing.B) {
+			linkedL := newListOfLength(length)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				linkedL.GetValue(length - 1)
+			}
+		})
+	}
+}
+
+func BenchmarkLength(b *testing.B) {
+	linkedL := newListOfLength(10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linkedL.Length()
+	}
+}
+
+func BenchmarkParallelInsertRemove(b *testing.B) {
+	linkedL := newListOfLength(10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			linkedL.InsertBefore(0, 0)
+			linkedL.Remove(0)
+		}
+	})
+}
+
+func BenchmarkParallelGetValue(b *testing.B) {
+	linkedL := newListOfLength(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			linkedL.GetValue(500)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package linkedlist
+
+import (
+	"datatypes/binarycodec"
+	"datatypes/internal/serialize"
+	"errors"
+	"io"
+)
+
+//*************** Snapshots ***************
+
+//Snapshot is an immutable, point-in-time view of a LinkedList. Safe to use concurrently.
+type Snapshot struct {
+	values []interface{}
+}
+
+//Snapshot captures the current content of the list.
+//Values are copied under a read lock, so concurrent writers are blocked only for the copy.
+//Returns an empty snapshot on an uninitialized LinkedList.
+func (ll *LinkedList) Snapshot() *Snapshot {
+	if ll == nil {
+		return &Snapshot{values: []interface{}{}}
+	}
+
+	ll.rwMutex.RLock()
+	defer ll.rwMutex.RUnlock()
+
+	return &Snapshot{values: ll.values()}
+}
+
+//Restore replaces the content of the list with the content of the snapshot.
+//Returns an error on an uninitialized LinkedList or a nil snapshot.
+func (ll *LinkedList) Restore(snapshot *Snapshot) error {
+	if ll == nil {
+		return errors.New("Linked list is nil")
+	}
+	if snapshot == nil {
+		return errors.New("Snapshot is nil")
+	}
+
+	ll.rwMutex.Lock()
+	defer ll.rwMutex.Unlock()
+
+	ll.replaceValues(snapshot.values)
+	return nil
+}
+
+//Load replaces the content of the list with a snapshot read from the reader.
+//Reads everything up to EOF, the data has to be written by Snapshot.WriteTo or Snapshot.WriteFile.
+func (ll *LinkedList) Load(reader io.Reader) error {
+	if ll == nil {
+		return errors.New("Linked list is nil")
+	}
+
+	return serialize.Load(reader, ll)
+}
+
+//Length returns the number of values in the snapshot.
+func (snapshot *Snapshot) Length() int {
+	if snapshot == nil {
+		return 0
+	}
+	return len(snapshot.values)
+}
+
+//Values returns a copy of the values in the snapshot, in list order.
+func (snapshot *Snapshot) Values() []interface{} {
+	if snapshot == nil {
+		return []interface{}{}
+	}
+	return serialize.Copy(snapshot.values)
+}
+
+//MarshalBinary encodes the snapshot in the same format as LinkedList.MarshalBinary.
+func (snapshot *Snapshot) MarshalBinary() ([]byte, error) {
+	return binarycodec.Encode(binarycodec.KindLinkedList, snapshot.Values(), binarycodec.GobCodec{})
+}
+
+//WriteTo writes the encoded snapshot to the writer. Implements io.WriterTo.
+func (snapshot *Snapshot) WriteTo(writer io.Writer) (int64, error) {
+	return serialize.WriteTo(writer, snapshot)
+}
+
+//WriteFile atomically replaces the file at path with the encoded snapshot. Suitable for checkpoint files.
+func (snapshot *Snapshot) WriteFile(path string) error {
+	return serialize.WriteFile(path, snapshot)
+}
@@ -0,0 +1,85 @@
+package linkedlist_test
+
+import (
+	. "datatypes/linkedlist"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+//*************** Snapshot Test ***************
+
+func TestSnapshot(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		list           *LinkedList
+		expectedValues string
+	}{
+		{nilList, "[]"},
+		{emptyList, "[]"},
+		{twoElementList, "[0 1]"},
+	}
+
+	for i, aCase := range cases {
+		snapshot := aCase.list.Snapshot()
+		if aCase.list != nil {
+			aCase.list.InsertBefore(0, "later")
+		}
+		if fmt.Sprint(snapshot.Values()) != aCase.expectedValues {
+			t.Errorf("Error in case %d. Expected values %s, got %v", i, aCase.expectedValues, snapshot.Values())
+		}
+	}
+}
+
+func TestRestoreAndLoad(t *testing.T) {
+	setVariablesToDefaults()
+	snapshot := tenElementList.Snapshot()
+	if err := oneElementList.Restore(snapshot); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := oneElementList.GetValue(9); value != "9" || oneElementList.Length() != 10 {
+		t.Errorf("Restored list is incorrect")
+	}
+
+	path := filepath.Join(t.TempDir(), "list.checkpoint")
+	if err := snapshot.WriteFile(path); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	defer file.Close()
+	if err := emptyList.Load(file); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if fmt.Sprint(emptyList.Snapshot().Values()) != fmt.Sprint(snapshot.Values()) {
+		t.Errorf("Loaded list doesn't match the snapshot")
+	}
+}
+
+//TestConcurrentSnapshot takes snapshots while other goroutines modify the list. Run with `go test -race`.
+func TestConcurrentSnapshot(t *testing.T) {
+	linkedL := NewLinkedList()
+	var waitGroup sync.WaitGroup
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		for i := 0; i < 500; i++ {
+			linkedL.InsertBefore(0, i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		values := linkedL.Snapshot().Values()
+		for j := 1; j < len(values); j++ {
+			if values[j].(int) != values[j-1].(int)-1 {
+				t.Fatalf("Snapshot is not consistent: %v", values)
+			}
+		}
+	}
+	waitGroup.Wait()
+}
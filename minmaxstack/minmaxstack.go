@@ -0,0 +1,260 @@
+//Minmaxstack is an implementation of a LIFO stack that keeps track of its minimum and maximum value.
+//Follows the design of stack.Stack, with two auxiliary chains holding the candidates for the minimum and maximum,
+//so Min and Max are O(1) after every Push and Pop.
+//Stacks of numbers can also keep a running sum for O(1) Sum and Mean.
+//Safe to use concurrently.
+package minmaxstack
+
+import (
+	"cmp"
+	"errors"
+	"sync"
+)
+
+//Make runtime asserts fatal
+const (
+	panic_on_internal_inconsistencies = true
+)
+
+//*************** Min Max Stack Public Interface ***************
+
+//Number is the set of types NewNumericStack accepts.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+//MinMaxStack is a LIFO stack with O(1) Min and Max. Goroutine safe.
+type MinMaxStack[T any] struct {
+	length     int
+	topElement *element[T]
+	//Tops of the auxiliary chains, the current minimum and maximum
+	minElement *element[T]
+	maxElement *element[T]
+	less       func(a T, b T) bool
+	//Converts values for Sum and Mean, nil if the stack doesn't track sums
+	toFloat func(value T) float64
+	rwMutex sync.RWMutex
+}
+
+//NewMinMaxStack initializes an empty MinMaxStack ordered by less. Recommended way of initialization.
+func NewMinMaxStack[T any](less func(a T, b T) bool) *MinMaxStack[T] {
+	if less == nil {
+		panic("Comparator is nil")
+	}
+	return &MinMaxStack[T]{less: less}
+}
+
+//NewOrderedStack initializes an empty MinMaxStack using the natural order of T.
+func NewOrderedStack[T cmp.Ordered]() *MinMaxStack[T] {
+	return NewMinMaxStack(cmp.Less[T])
+}
+
+//NewNumericStack initializes an empty MinMaxStack of numbers that also tracks Sum and Mean.
+func NewNumericStack[T Number]() *MinMaxStack[T] {
+	s := NewMinMaxStack(cmp.Less[T])
+	s.toFloat = func(value T) float64 { return float64(value) }
+	return s
+}
+
+//NewMinMaxStackWithSum initializes an empty MinMaxStack ordered by less that tracks Sum and Mean of toFloat(value).
+func NewMinMaxStackWithSum[T any](less func(a T, b T) bool, toFloat func(value T) float64) *MinMaxStack[T] {
+	if toFloat == nil {
+		panic("Conversion function is nil")
+	}
+	s := NewMinMaxStack(less)
+	s.toFloat = toFloat
+	return s
+}
+
+//Length returns the current number of values in the stack. Returns 0 on an uninitialized stack.
+func (s *MinMaxStack[T]) Length() int {
+	if s == nil {
+		return 0
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.length
+}
+
+//Peek returns the value at the top of the stack without removing it.
+//If the stack is empty or nil, returns an error.
+func (s *MinMaxStack[T]) Peek() (value T, err error) {
+	if s == nil {
+		return value, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.valueOf(s.topElement)
+}
+
+//Min returns the smallest value in the stack. Of equal values, the one pushed last is returned.
+//If the stack is empty or nil, returns an error.
+func (s *MinMaxStack[T]) Min() (value T, err error) {
+	if s == nil {
+		return value, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.valueOf(s.minElement)
+}
+
+//Max returns the largest value in the stack. Of equal values, the one pushed last is returned.
+//If the stack is empty or nil, returns an error.
+func (s *MinMaxStack[T]) Max() (value T, err error) {
+	if s == nil {
+		return value, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.valueOf(s.maxElement)
+}
+
+//Sum returns the sum of all values. Returns 0 for an empty stack.
+//If the stack doesn't track sums or is nil, returns an error.
+func (s *MinMaxStack[T]) Sum() (sum float64, err error) {
+	if s == nil {
+		return 0, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	if s.toFloat == nil {
+		return 0, errors.New("Stack doesn't track sums")
+	}
+	if s.topElement == nil {
+		return 0, nil
+	}
+	return s.topElement.sum, nil
+}
+
+//Mean returns the average of all values.
+//If the stack is empty, doesn't track sums or is nil, returns an error.
+func (s *MinMaxStack[T]) Mean() (mean float64, err error) {
+	if s == nil {
+		return 0, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	if s.toFloat == nil {
+		return 0, errors.New("Stack doesn't track sums")
+	}
+	if s.topElement == nil {
+		return 0, errors.New("Stack is empty")
+	}
+	return s.topElement.sum / float64(s.length), nil
+}
+
+//Push ads value to the top of the stack.
+//Panics on an uninitialized stack.
+func (s *MinMaxStack[T]) Push(value T) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	newElem := &element[T]{value: value, previousElement: s.topElement}
+	if s.toFloat != nil {
+		newElem.sum = s.toFloat(value)
+		if s.topElement != nil {
+			newElem.sum += s.topElement.sum
+		}
+	}
+
+	//Join a chain when the value is at least as small (large) as the current minimum (maximum)
+	if s.minElement == nil || !s.less(s.minElement.value, value) {
+		newElem.previousMin = s.minElement
+		newElem.onMinChain = true
+		s.minElement = newElem
+	}
+	if s.maxElement == nil || !s.less(value, s.maxElement.value) {
+		newElem.previousMax = s.maxElement
+		newElem.onMaxChain = true
+		s.maxElement = newElem
+	}
+
+	s.topElement = newElem
+	s.changeLength(1)
+}
+
+//Pop removes the value from the top of the stack.
+//If the stack is empty, returns an error.
+//Panics on an uninitialized stack.
+func (s *MinMaxStack[T]) Pop() (value T, err error) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	topElement := s.topElement
+	if topElement == nil {
+		return value, errors.New("Stack is empty")
+	}
+
+	//The top of the stack is on a chain only if it is also the top of that chain
+	if topElement.onMinChain {
+		if s.minElement != topElement && panic_on_internal_inconsistencies {
+			panic("Top element is on the min chain, but not its top")
+		}
+		s.minElement = topElement.previousMin
+	}
+	if topElement.onMaxChain {
+		if s.maxElement != topElement && panic_on_internal_inconsistencies {
+			panic("Top element is on the max chain, but not its top")
+		}
+		s.maxElement = topElement.previousMax
+	}
+
+	s.topElement = topElement.previousElement
+	s.changeLength(-1)
+	return topElement.value, nil
+}
+
+//*************** Min Max Stack Internal Structure ***************
+
+type element[T any] struct {
+	value           T
+	previousElement *element[T]
+	//Links of the auxiliary chains, set only for elements on them
+	previousMin *element[T]
+	previousMax *element[T]
+	onMinChain  bool
+	onMaxChain  bool
+	//Sum of this value and every value below it
+	sum float64
+}
+
+//valueOf returns the value of an element, an error if there is none. No locking.
+func (s *MinMaxStack[T]) valueOf(anElement *element[T]) (value T, err error) {
+	if anElement == nil {
+		if s.length != 0 && panic_on_internal_inconsistencies {
+			panic("Stack is not empty, but element is nil")
+		}
+		return value, errors.New("Stack is empty")
+	}
+	return anElement.value, nil
+}
+
+func (s *MinMaxStack[T]) changeLength(delta int) {
+	s.length += delta
+
+	if s.length < 0 && panic_on_internal_inconsistencies {
+		panic("Stack has negative length")
+	}
+}
@@ -0,0 +1,184 @@
+package minmaxstack_test
+
+import (
+	. "datatypes/minmaxstack"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestMinMaxAfterEveryOperation(t *testing.T) {
+	s := NewNumericStack[int]()
+	if _, err := s.Min(); err == nil {
+		t.Errorf("Expected an error for Min on an empty stack")
+	}
+	if _, err := s.Mean(); err == nil {
+		t.Errorf("Expected an error for Mean on an empty stack")
+	}
+
+	cases := []struct {
+		push        bool
+		value       int
+		expectedMin int
+		expectedMax int
+		expectedSum float64
+	}{
+		{push: true, value: 5, expectedMin: 5, expectedMax: 5, expectedSum: 5},
+		{push: true, value: 3, expectedMin: 3, expectedMax: 5, expectedSum: 8},
+		{push: true, value: 3, expectedMin: 3, expectedMax: 5, expectedSum: 11},
+		{push: true, value: 9, expectedMin: 3, expectedMax: 9, expectedSum: 20},
+		{push: false, expectedMin: 3, expectedMax: 5, expectedSum: 11},
+		{push: false, expectedMin: 3, expectedMax: 5, expectedSum: 8},
+		{push: false, expectedMin: 5, expectedMax: 5, expectedSum: 5},
+	}
+
+	for i, aCase := range cases {
+		if aCase.push {
+			s.Push(aCase.value)
+		} else {
+			s.Pop()
+		}
+		min, _ := s.Min()
+		max, _ := s.Max()
+		sum, _ := s.Sum()
+		if min != aCase.expectedMin || max != aCase.expectedMax || sum != aCase.expectedSum {
+			t.Errorf("Error in case %d. Expected %d, %d, %v, got %d, %d, %v", i, aCase.expectedMin, aCase.expectedMax, aCase.expectedSum, min, max, sum)
+		}
+	}
+
+	s.Pop()
+	if _, err := s.Max(); err == nil || s.Length() != 0 {
+		t.Errorf("Expected an empty stack")
+	}
+	if _, err := s.Pop(); err == nil {
+		t.Errorf("Expected an error when popping an empty stack")
+	}
+}
+
+func TestAgainstScan(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	s := NewNumericStack[float64]()
+	reference := []float64{}
+
+	for i := 0; i < 5000; i++ {
+		if len(reference) > 0 && random.Intn(3) == 0 {
+			value, _ := s.Pop()
+			if value != reference[len(reference)-1] {
+				t.Fatalf("Expected to pop %v, got %v", reference[len(reference)-1], value)
+			}
+			reference = reference[:len(reference)-1]
+		} else {
+			value := float64(random.Intn(50))
+			s.Push(value)
+			reference = append(reference, value)
+		}
+		if len(reference) == 0 {
+			continue
+		}
+
+		expectedMin, expectedMax, expectedSum := reference[0], reference[0], 0.0
+		for _, value := range reference {
+			if value < expectedMin {
+				expectedMin = value
+			}
+			if value > expectedMax {
+				expectedMax = value
+			}
+			expectedSum += value
+		}
+		min, _ := s.Min()
+		max, _ := s.Max()
+		mean, _ := s.Mean()
+		if min != expectedMin || max != expectedMax || mean != expectedSum/float64(len(reference)) {
+			t.Fatalf("Step %d. Expected %v, %v, %v, got %v, %v, %v", i, expectedMin, expectedMax, expectedSum/float64(len(reference)), min, max, mean)
+		}
+	}
+}
+
+func TestComparator(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	s := NewMinMaxStack(func(a task, b task) bool { return a.priority < b.priority })
+	s.Push(task{"write", 2})
+	s.Push(task{"read", 1})
+	s.Push(task{"urgent", 1})
+
+	//Equal values - the one pushed last wins
+	if min, _ := s.Min(); min.name != "urgent" {
+		t.Errorf("Expected urgent, got %s", min.name)
+	}
+	s.Pop()
+	if min, _ := s.Min(); min.name != "read" {
+		t.Errorf("Expected read, got %s", min.name)
+	}
+	if _, err := s.Sum(); err == nil {
+		t.Errorf("Expected an error for Sum on a stack without sums")
+	}
+
+	words := NewOrderedStack[string]()
+	for _, word := range strings.Fields("pear apple zucchini fig") {
+		words.Push(word)
+	}
+	min, _ := words.Min()
+	max, _ := words.Max()
+	if min != "apple" || max != "zucchini" {
+		t.Errorf("Expected apple and zucchini, got %s and %s", min, max)
+	}
+
+	lengths := NewMinMaxStackWithSum(func(a string, b string) bool { return len(a) < len(b) }, func(value string) float64 { return float64(len(value)) })
+	lengths.Push("ab")
+	lengths.Push("abcd")
+	if mean, _ := lengths.Mean(); mean != 3 {
+		t.Errorf("Expected mean length 3, got %v", mean)
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	s := NewNumericStack[int64]()
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int64) {
+			defer wg.Done()
+			for i := int64(0); i < 1000; i++ {
+				s.Push(worker*1000 + i)
+				s.Min()
+				s.Max()
+				if i%2 == 0 {
+					s.Pop()
+				}
+			}
+		}(int64(worker))
+	}
+	wg.Wait()
+
+	if s.Length() != 4000 {
+		t.Errorf("Expected length 4000, got %d", s.Length())
+	}
+}
+
+func TestNil(t *testing.T) {
+	var s *MinMaxStack[int]
+	if _, err := s.Min(); err == nil || s.Length() != 0 {
+		t.Errorf("Expected an error on a nil stack")
+	}
+}
+
+func ExampleMinMaxStack() {
+	readings := NewNumericStack[float64]()
+	for _, reading := range []float64{21.5, 19, 23.5} {
+		readings.Push(reading)
+	}
+
+	min, _ := readings.Min()
+	max, _ := readings.Max()
+	mean, _ := readings.Mean()
+	fmt.Println(min, max, mean)
+	//Output: 19 23.5 21.333333333333332
+}
@@ -0,0 +1,202 @@
+//Persistentlist is an implementation of an immutable singly linked list, a cons list.
+//Every operation returns a new version and leaves the old one intact, versions share their common tails.
+//Adding or removing at the front is O(1), changes at an index copy only the elements before it.
+//Every version can be read from any goroutine without locking.
+//The nil *List is the empty list.
+package persistentlist
+
+import (
+	"datatypes/internal/equality"
+	"datatypes/linkedlist"
+	"errors"
+)
+
+//*************** Persistent List Public Interface ***************
+
+//List is an immutable singly linked list. Every non-empty version is its first value linked to the rest.
+//Goroutine safe, a List never changes after it is created. Uses zero based indexing.
+type List struct {
+	value interface{}
+	next  *List
+	//Number of values in this version
+	length int
+}
+
+//Empty returns the empty list. Same as a nil *List.
+func Empty() *List {
+	return nil
+}
+
+//Of returns a list holding the values in the given order.
+func Of(values ...interface{}) *List {
+	var l *List
+	for i := len(values) - 1; i >= 0; i-- {
+		l = l.Prepend(values[i])
+	}
+	return l
+}
+
+//Length returns the number of values in the list.
+func (l *List) Length() int {
+	if l == nil {
+		return 0
+	}
+	return l.length
+}
+
+//IsEmpty reports whether the list has no values.
+func (l *List) IsEmpty() bool {
+	return l == nil
+}
+
+//Head returns the first value.
+//If the list is empty, returns an error.
+func (l *List) Head() (value interface{}, err error) {
+	if l == nil {
+		return nil, errors.New("List is empty")
+	}
+	return l.value, nil
+}
+
+//Tail returns the list without its first value, sharing every element with this one.
+//If the list is empty, returns an error and the empty list.
+func (l *List) Tail() (*List, error) {
+	if l == nil {
+		return nil, errors.New("List is empty")
+	}
+	return l.next, nil
+}
+
+//Prepend returns a new list with value in front of this one. O(1).
+func (l *List) Prepend(value interface{}) *List {
+	return &List{value: value, next: l, length: l.Length() + 1}
+}
+
+//GetValue returns the value at index.
+//If the index is out of range, returns an error.
+func (l *List) GetValue(index int) (value interface{}, err error) {
+	if index < 0 || index >= l.Length() {
+		return nil, errors.New("Index out of range")
+	}
+	return l.elementAt(index).value, nil
+}
+
+//Append returns a new list with value after the last one. Copies the whole list.
+func (l *List) Append(value interface{}) *List {
+	list, _ := l.InsertBefore(l.Length(), value)
+	return list
+}
+
+//InsertBefore returns a new list with value at index, the values from index on move back by one.
+//An index equal to the length appends. Copies the elements before index, shares the rest.
+//If the index is out of range, returns an error and this list.
+func (l *List) InsertBefore(index int, value interface{}) (*List, error) {
+	if index < 0 || index > l.Length() {
+		return l, errors.New("Index out of range")
+	}
+	suffix := l.elementAt(index)
+	return l.copyPrefix(index, suffix.Prepend(value)), nil
+}
+
+//Remove returns the value at index and a new list without it. Copies the elements before index, shares the rest.
+//If the index is out of range, returns an error and this list.
+func (l *List) Remove(index int) (removedValue interface{}, rest *List, err error) {
+	if index < 0 || index >= l.Length() {
+		return nil, l, errors.New("Index out of range")
+	}
+	removed := l.elementAt(index)
+	return removed.value, l.copyPrefix(index, removed.next), nil
+}
+
+//Reverse returns a new list with the values in reverse order.
+func (l *List) Reverse() *List {
+	var reversed *List
+	l.Each(func(value interface{}) bool {
+		reversed = reversed.Prepend(value)
+		return true
+	})
+	return reversed
+}
+
+//Each calls visit with every value from the first on, until visit returns false.
+func (l *List) Each(visit func(value interface{}) bool) {
+	for current := l; current != nil; current = current.next {
+		if !visit(current.value) {
+			return
+		}
+	}
+}
+
+//Values returns the values in order.
+func (l *List) Values() []interface{} {
+	values := make([]interface{}, 0, l.Length())
+	l.Each(func(value interface{}) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+//Equal reports whether both lists hold equal values in the same order. Values are compared with ==,
+//values that can't be compared never match, unless both lists share the element holding them.
+//Stops at the first shared element, so versions derived from each other compare in time proportional to where they differ.
+func (l *List) Equal(other *List) bool {
+	return l.EqualFunc(other, equality.Equal)
+}
+
+//EqualFunc is Equal with a custom comparison of values.
+func (l *List) EqualFunc(other *List, equal func(first interface{}, second interface{}) bool) bool {
+	if l.Length() != other.Length() {
+		return false
+	}
+	for first, second := l, other; first != second; first, second = first.next, second.next {
+		if !equal(first.value, second.value) {
+			return false
+		}
+	}
+	return true
+}
+
+//*************** Conversion ***************
+
+//FromLinkedList returns a persistent copy of a mutable list. The mutable list is read under its own lock and not changed.
+//Returns the empty list for a nil list.
+func FromLinkedList(mutable *linkedlist.LinkedList) *List {
+	if mutable == nil {
+		return nil
+	}
+	return Of(mutable.Snapshot().Values()...)
+}
+
+//ToLinkedList returns a new mutable list holding the same values.
+func (l *List) ToLinkedList() *linkedlist.LinkedList {
+	mutable := linkedlist.NewLinkedList()
+	l.Each(func(value interface{}) bool {
+		mutable.Append(value)
+		return true
+	})
+	return mutable
+}
+
+//*************** Persistent List Internal Structure ***************
+
+//elementAt returns the version starting at index, nil for an index equal to the length.
+func (l *List) elementAt(index int) *List {
+	current := l
+	for i := 0; i < index; i++ {
+		current = current.next
+	}
+	return current
+}
+
+//copyPrefix returns copies of the first count elements linked in front of suffix.
+func (l *List) copyPrefix(count int, suffix *List) *List {
+	prefix := make([]interface{}, 0, count)
+	for current := l; len(prefix) < count; current = current.next {
+		prefix = append(prefix, current.value)
+	}
+	for i := len(prefix) - 1; i >= 0; i-- {
+		suffix = suffix.Prepend(prefix[i])
+	}
+	return suffix
+}
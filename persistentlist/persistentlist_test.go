@@ -0,0 +1,129 @@
+package persistentlist_test
+
+import (
+	"datatypes/linkedlist"
+	. "datatypes/persistentlist"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestOperationsKeepOldVersions(t *testing.T) {
+	original := Of("a", "b", "c")
+
+	appended := original.Append("d")
+	prepended := original.Prepend("z")
+	inserted, err := original.InsertBefore(1, "x")
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err.Error())
+	}
+	removedValue, removed, err := original.Remove(1)
+	if err != nil || removedValue != "b" {
+		t.Fatalf("Expected to remove b, got %v with error %v", removedValue, err)
+	}
+
+	cases := []struct {
+		version        *List
+		expectedValues string
+	}{
+		{version: original, expectedValues: "[a b c]"},
+		{version: appended, expectedValues: "[a b c d]"},
+		{version: prepended, expectedValues: "[z a b c]"},
+		{version: inserted, expectedValues: "[a x b c]"},
+		{version: removed, expectedValues: "[a c]"},
+		{version: original.Reverse(), expectedValues: "[c b a]"},
+	}
+	for i, aCase := range cases {
+		if values := fmt.Sprint(aCase.version.Values()); values != aCase.expectedValues || aCase.version.Length() != len(aCase.version.Values()) {
+			t.Errorf("Error in case %d. Expected %s, got %s with length %d", i, aCase.expectedValues, values, aCase.version.Length())
+		}
+	}
+
+	if tail, _ := prepended.Tail(); tail != original {
+		t.Errorf("Expected Prepend to share the original list")
+	}
+}
+
+func TestIndexErrors(t *testing.T) {
+	l := Of(1, 2)
+	if _, err := l.GetValue(2); err == nil {
+		t.Errorf("Expected an error for GetValue out of range")
+	}
+	if value, _ := l.GetValue(1); value != 2 {
+		t.Errorf("Expected 2, got %v", value)
+	}
+	if same, err := l.InsertBefore(3, 0); err == nil || same != l {
+		t.Errorf("Expected an error and the same list for InsertBefore out of range")
+	}
+	if _, same, err := l.Remove(-1); err == nil || same != l {
+		t.Errorf("Expected an error and the same list for Remove out of range")
+	}
+
+	empty := Empty()
+	if _, err := empty.Head(); err == nil || !empty.IsEmpty() {
+		t.Errorf("Expected an error for Head on an empty list")
+	}
+	if _, err := empty.Tail(); err == nil {
+		t.Errorf("Expected an error for Tail on an empty list")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	shared := Of(3, 4, 5)
+	cases := []struct {
+		first    *List
+		second   *List
+		expected bool
+	}{
+		{first: Of(1, 2), second: Of(1, 2), expected: true},
+		{first: Of(1, 2), second: Of(1, 3), expected: false},
+		{first: Of(1), second: Of(1, 2), expected: false},
+		{first: shared.Prepend(1), second: shared.Prepend(1), expected: true},
+		{first: Of([]int{1}), second: Of([]int{1}), expected: false},
+		{first: Empty(), second: Of(), expected: true},
+	}
+	for i, aCase := range cases {
+		if aCase.first.Equal(aCase.second) != aCase.expected {
+			t.Errorf("Error in case %d. Expected Equal to be %v", i, aCase.expected)
+		}
+	}
+
+	caseInsensitive := func(first interface{}, second interface{}) bool {
+		return strings.EqualFold(first.(string), second.(string))
+	}
+	if !Of("A", "b").EqualFunc(Of("a", "B"), caseInsensitive) {
+		t.Errorf("Expected EqualFunc to use the comparison")
+	}
+}
+
+func TestConversion(t *testing.T) {
+	mutable := linkedlist.NewLinkedList()
+	mutable.Append(1)
+	mutable.Append(2)
+
+	persistent := FromLinkedList(mutable)
+	mutable.Append(3)
+	if fmt.Sprint(persistent.Values()) != "[1 2]" {
+		t.Errorf("Expected [1 2], got %v", persistent.Values())
+	}
+
+	back := persistent.Prepend(0).ToLinkedList()
+	if back.Length() != 3 {
+		t.Fatalf("Expected length 3, got %d", back.Length())
+	}
+	for i := 0; i < 3; i++ {
+		if value, _ := back.GetValue(i); value != i {
+			t.Errorf("Expected %d at index %d, got %v", i, i, value)
+		}
+	}
+}
+
+func ExampleList() {
+	events := Of("created", "paid")
+	shipped := events.Append("shipped")
+
+	fmt.Println(events.Values(), shipped.Values())
+	//Output: [created paid] [created paid shipped]
+}
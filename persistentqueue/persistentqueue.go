@@ -0,0 +1,235 @@
+//Persistentqueue is an implementation of an immutable FIFO queue, Okasaki's banker's queue.
+//Values are enqueued onto a rear persistentlist.List and dequeued from a lazy front stream.
+//Whenever the rear grows longer than the front, it is reversed and appended to the front lazily,
+//which keeps Enqueue and Dequeue at amortized O(1) even when old versions are used again.
+//Every version can be read from any goroutine without locking, lazy parts are evaluated once under a sync.Once.
+//The nil *Queue is the empty queue.
+package persistentqueue
+
+import (
+	"datatypes/internal/equality"
+	"datatypes/persistentlist"
+	"datatypes/queue"
+	"errors"
+	"sync"
+)
+
+//*************** Persistent Queue Public Interface ***************
+
+//Queue is an immutable FIFO queue. Goroutine safe, a Queue never changes after it is created.
+type Queue struct {
+	front       *stream
+	frontLength int
+	//Newest value first
+	rear       *persistentlist.List
+	rearLength int
+}
+
+//Empty returns the empty queue. Same as a nil *Queue.
+func Empty() *Queue {
+	return nil
+}
+
+//Of returns a queue holding the values, the first one at the front.
+func Of(values ...interface{}) *Queue {
+	var q *Queue
+	for _, value := range values {
+		q = q.Enqueue(value)
+	}
+	return q
+}
+
+//Length returns the number of values in the queue.
+func (q *Queue) Length() int {
+	if q == nil {
+		return 0
+	}
+	return q.frontLength + q.rearLength
+}
+
+//IsEmpty reports whether the queue has no values.
+func (q *Queue) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+//Peek returns the value at the front of the queue.
+//If the queue is empty, returns an error.
+func (q *Queue) Peek() (value interface{}, err error) {
+	if q.IsEmpty() {
+		return nil, errors.New("Queue is empty")
+	}
+	return q.front.force().head, nil
+}
+
+//Enqueue returns a new queue with value at the back. This queue is not changed.
+func (q *Queue) Enqueue(value interface{}) *Queue {
+	if q == nil {
+		return balance(nil, 0, persistentlist.Of(value), 1)
+	}
+	return balance(q.front, q.frontLength, q.rear.Prepend(value), q.rearLength+1)
+}
+
+//Dequeue returns the value at the front and the queue behind it. This queue is not changed.
+//If the queue is empty, returns an error and the empty queue.
+func (q *Queue) Dequeue() (value interface{}, rest *Queue, err error) {
+	if q.IsEmpty() {
+		return nil, nil, errors.New("Queue is empty")
+	}
+	frontCell := q.front.force()
+	return frontCell.head, balance(frontCell.tail, q.frontLength-1, q.rear, q.rearLength), nil
+}
+
+//Each calls visit with every value from the front to the back, until visit returns false.
+func (q *Queue) Each(visit func(value interface{}) bool) {
+	if q.IsEmpty() {
+		return
+	}
+	for current := q.front.force(); current != nil; current = current.tail.force() {
+		if !visit(current.head) {
+			return
+		}
+	}
+	//The rear holds the newest values first
+	rearValues := q.rear.Values()
+	for i := len(rearValues) - 1; i >= 0; i-- {
+		if !visit(rearValues[i]) {
+			return
+		}
+	}
+}
+
+//Values returns the values from front to back.
+func (q *Queue) Values() []interface{} {
+	values := make([]interface{}, 0, q.Length())
+	q.Each(func(value interface{}) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+//Equal reports whether both queues hold equal values in the same order. Values are compared with ==,
+//values that can't be compared never match, unless both queues share the version holding them.
+//Stops at the first shared version, queues of different lengths are told apart in O(1).
+func (q *Queue) Equal(other *Queue) bool {
+	return q.EqualFunc(other, equality.Equal)
+}
+
+//EqualFunc is Equal with a custom comparison of values.
+func (q *Queue) EqualFunc(other *Queue, equal func(first interface{}, second interface{}) bool) bool {
+	if q == other {
+		return true
+	}
+	if q.Length() != other.Length() {
+		return false
+	}
+
+	first, second := q, other
+	for !first.IsEmpty() {
+		//Versions dequeued from a common version share the rest of their values
+		if first.front == second.front && first.rear == second.rear {
+			return true
+		}
+		firstValue, firstRest, _ := first.Dequeue()
+		secondValue, secondRest, _ := second.Dequeue()
+		if !equal(firstValue, secondValue) {
+			return false
+		}
+		first, second = firstRest, secondRest
+	}
+	return true
+}
+
+//*************** Conversion ***************
+
+//FromQueue returns a persistent copy of a mutable queue. The mutable queue is read under its own lock and not changed.
+//Expired values are left out. Returns the empty queue for a nil queue.
+func FromQueue(mutable *queue.Queue) *Queue {
+	if mutable == nil {
+		return nil
+	}
+	return Of(mutable.Snapshot().Values()...)
+}
+
+//ToQueue returns a new mutable queue holding the same values in the same order.
+func (q *Queue) ToQueue() *queue.Queue {
+	mutable := queue.NewQueue()
+	q.Each(func(value interface{}) bool {
+		mutable.Enqueue(value)
+		return true
+	})
+	return mutable
+}
+
+//*************** Persistent Queue Internal Structure ***************
+
+//stream is a lazily evaluated list. The nil *stream is the empty stream.
+type stream struct {
+	once  sync.Once
+	thunk func() *cell
+	cell  *cell
+}
+
+//cell is an evaluated stream element, nil at the end of the stream.
+type cell struct {
+	head interface{}
+	tail *stream
+}
+
+//suspend returns a stream evaluated by thunk when first forced.
+func suspend(thunk func() *cell) *stream {
+	return &stream{thunk: thunk}
+}
+
+//force evaluates the stream once and returns its first cell, nil if the stream is empty.
+func (s *stream) force() *cell {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		s.cell = s.thunk()
+		s.thunk = nil
+	})
+	return s.cell
+}
+
+//evaluated returns a stream that is already evaluated to the cell.
+func evaluated(c *cell) *stream {
+	s := &stream{cell: c}
+	s.once.Do(func() {})
+	return s
+}
+
+//appendStreams returns first followed by second, evaluated one cell at a time.
+func appendStreams(first *stream, second *stream) *stream {
+	return suspend(func() *cell {
+		firstCell := first.force()
+		if firstCell == nil {
+			return second.force()
+		}
+		return &cell{head: firstCell.head, tail: appendStreams(firstCell.tail, second)}
+	})
+}
+
+//reverseList returns a stream of the list's values in reverse order, built in one go when first forced.
+func reverseList(list *persistentlist.List) *stream {
+	return suspend(func() *cell {
+		var reversed *stream
+		list.Each(func(value interface{}) bool {
+			reversed = evaluated(&cell{head: value, tail: reversed})
+			return true
+		})
+		return reversed.force()
+	})
+}
+
+//balance builds a queue, moving the rear to the end of the front once the rear is longer.
+func balance(front *stream, frontLength int, rear *persistentlist.List, rearLength int) *Queue {
+	if frontLength+rearLength == 0 {
+		return nil
+	}
+	if rearLength <= frontLength {
+		return &Queue{front: front, frontLength: frontLength, rear: rear, rearLength: rearLength}
+	}
+	return &Queue{front: appendStreams(front, reverseList(rear)), frontLength: frontLength + rearLength}
+}
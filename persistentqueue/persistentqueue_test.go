@@ -0,0 +1,151 @@
+package persistentqueue_test
+
+import (
+	. "datatypes/persistentqueue"
+	"datatypes/queue"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestFifo(t *testing.T) {
+	var q *Queue
+	if _, _, err := q.Dequeue(); err == nil || !q.IsEmpty() {
+		t.Errorf("Expected an error for Dequeue on an empty queue")
+	}
+
+	for i := 0; i < 100; i++ {
+		q = q.Enqueue(i)
+		if i%3 == 0 {
+			value, rest, _ := q.Dequeue()
+			if value != i/3 {
+				t.Fatalf("Expected %d, got %v", i/3, value)
+			}
+			q = rest
+		}
+	}
+	if q.Length() != 66 {
+		t.Fatalf("Expected length 66, got %d", q.Length())
+	}
+	if front, _ := q.Peek(); front != 34 {
+		t.Errorf("Expected 34 at the front, got %v", front)
+	}
+	values := q.Values()
+	if len(values) != 66 || values[0] != 34 || values[65] != 99 {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestOldVersionsAgainstOracle(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	versions := []*Queue{nil}
+	oracles := [][]interface{}{{}}
+
+	for step := 0; step < 3000; step++ {
+		//Work on a random old version, which is where the lazy front matters
+		index := random.Intn(len(versions))
+		version, oracle := versions[index], oracles[index]
+
+		if len(oracle) > 0 && random.Intn(2) == 0 {
+			value, rest, err := version.Dequeue()
+			if err != nil || value != oracle[0] {
+				t.Fatalf("Step %d. Expected %v, got %v with error %v", step, oracle[0], value, err)
+			}
+			versions = append(versions, rest)
+			oracles = append(oracles, oracle[1:])
+		} else {
+			versions = append(versions, version.Enqueue(step))
+			oracles = append(oracles, append(append([]interface{}{}, oracle...), step))
+		}
+	}
+
+	for i, version := range versions {
+		if fmt.Sprint(version.Values()) != fmt.Sprint(oracles[i]) {
+			t.Fatalf("Version %d. Expected %v, got %v", i, oracles[i], version.Values())
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	_, dequeued, _ := Of(0, 1, 2).Dequeue()
+	shared := Of(0, map[int]int{}, 2)
+	_, firstShared, _ := shared.Dequeue()
+	_, secondShared, _ := shared.Dequeue()
+	cases := []struct {
+		first    *Queue
+		second   *Queue
+		expected bool
+	}{
+		{first: Of(1, 2), second: Of(1, 2), expected: true},
+		//Same values, different internal layout
+		{first: dequeued, second: Of(1, 2), expected: true},
+		{first: Of(1, 2), second: Of(2, 1), expected: false},
+		{first: Of(1, 2), second: Of(1, 2, 3), expected: false},
+		{first: Of(map[int]int{}), second: Of(map[int]int{}), expected: false},
+		//Separate versions sharing the values that can't be compared
+		{first: firstShared, second: secondShared, expected: true},
+		{first: Empty(), second: Of(), expected: true},
+	}
+	for i, aCase := range cases {
+		if aCase.first.Equal(aCase.second) != aCase.expected {
+			t.Errorf("Error in case %d. Expected Equal to be %v", i, aCase.expected)
+		}
+	}
+}
+
+func TestConversion(t *testing.T) {
+	mutable := queue.NewQueue()
+	mutable.Enqueue("a")
+	mutable.Enqueue("b")
+
+	persistent := FromQueue(mutable)
+	mutable.Dequeue()
+	if fmt.Sprint(persistent.Values()) != "[a b]" {
+		t.Errorf("Expected [a b], got %v", persistent.Values())
+	}
+
+	back := persistent.Enqueue("c").ToQueue()
+	for _, expected := range []string{"a", "b", "c"} {
+		if value, err := back.Dequeue(); err != nil || value != expected {
+			t.Errorf("Expected %s, got %v with error %v", expected, value, err)
+		}
+	}
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	shared := Of()
+	for i := 0; i < 1000; i++ {
+		shared = shared.Enqueue(i)
+	}
+
+	//All readers force the same lazy front at the same time
+	wg := sync.WaitGroup{}
+	for reader := 0; reader < 8; reader++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q := shared
+			for i := 0; i < 1000; i++ {
+				value, rest, err := q.Dequeue()
+				if err != nil || value != i {
+					t.Errorf("Expected %d, got %v with error %v", i, value, err)
+					return
+				}
+				q = rest
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func ExampleQueue() {
+	pending := Of("first", "second")
+	value, remaining, _ := pending.Dequeue()
+	later := remaining.Enqueue("third")
+
+	fmt.Println(value, pending.Values(), later.Values())
+	//Output: first [first second] [second third]
+}
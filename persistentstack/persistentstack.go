@@ -0,0 +1,112 @@
+//Persistentstack is an implementation of an immutable LIFO stack.
+//Push and Pop never change a stack, they return a new version sharing every value below the top with the old one,
+//both in O(1). Every version stays valid and can be read from any goroutine without locking.
+//The nil *Stack is the empty stack.
+package persistentstack
+
+import (
+	"datatypes/stack"
+	"errors"
+)
+
+//*************** Persistent Stack Public Interface ***************
+
+//Stack is an immutable LIFO stack. Every non-empty version is its top value linked to the version below it.
+//Goroutine safe, a Stack never changes after it is created.
+type Stack struct {
+	value interface{}
+	below *Stack
+	//Number of values in this version
+	length int
+}
+
+//Empty returns the empty stack. Same as a nil *Stack.
+func Empty() *Stack {
+	return nil
+}
+
+//Of returns a stack holding the values, the last one on top.
+func Of(values ...interface{}) *Stack {
+	var s *Stack
+	for _, value := range values {
+		s = s.Push(value)
+	}
+	return s
+}
+
+//Length returns the number of values in the stack.
+func (s *Stack) Length() int {
+	if s == nil {
+		return 0
+	}
+	return s.length
+}
+
+//IsEmpty reports whether the stack has no values.
+func (s *Stack) IsEmpty() bool {
+	return s == nil
+}
+
+//Peek returns the value at the top of the stack.
+//If the stack is empty, returns an error.
+func (s *Stack) Peek() (value interface{}, err error) {
+	if s == nil {
+		return nil, errors.New("Stack is empty")
+	}
+	return s.value, nil
+}
+
+//Push returns a new stack with value on top of this one. This stack is not changed.
+func (s *Stack) Push(value interface{}) *Stack {
+	return &Stack{value: value, below: s, length: s.Length() + 1}
+}
+
+//Pop returns the top value and the stack below it. This stack is not changed.
+//If the stack is empty, returns an error and the empty stack.
+func (s *Stack) Pop() (value interface{}, rest *Stack, err error) {
+	if s == nil {
+		return nil, nil, errors.New("Stack is empty")
+	}
+	return s.value, s.below, nil
+}
+
+//Each calls visit with every value from the top down, until visit returns false.
+func (s *Stack) Each(visit func(value interface{}) bool) {
+	for current := s; current != nil; current = current.below {
+		if !visit(current.value) {
+			return
+		}
+	}
+}
+
+//Values returns the values from bottom to top, the order they were pushed in, like stack.Snapshot.Values.
+func (s *Stack) Values() []interface{} {
+	values := make([]interface{}, s.Length())
+	i := len(values) - 1
+	s.Each(func(value interface{}) bool {
+		values[i] = value
+		i--
+		return true
+	})
+	return values
+}
+
+//*************** Conversion ***************
+
+//FromStack returns a persistent copy of a mutable stack. The mutable stack is read under its own lock and not changed.
+//Expired values are left out. Returns the empty stack for a nil stack.
+func FromStack(mutable *stack.Stack) *Stack {
+	if mutable == nil {
+		return nil
+	}
+	return Of(mutable.Snapshot().Values()...)
+}
+
+//ToStack returns a new mutable stack holding the same values, top on top.
+func (s *Stack) ToStack() *stack.Stack {
+	mutable := stack.NewStack()
+	for _, value := range s.Values() {
+		mutable.Push(value)
+	}
+	return mutable
+}
@@ -0,0 +1,122 @@
+package persistentstack_test
+
+import (
+	. "datatypes/persistentstack"
+	"datatypes/stack"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//*************** Public Interface Test ***************
+
+func TestVersions(t *testing.T) {
+	empty := Empty()
+	if !empty.IsEmpty() || empty.Length() != 0 {
+		t.Errorf("Expected an empty stack")
+	}
+	if _, err := empty.Peek(); err == nil {
+		t.Errorf("Expected an error for Peek on an empty stack")
+	}
+	if _, rest, err := empty.Pop(); err == nil || rest != nil {
+		t.Errorf("Expected an error and the empty stack for Pop on an empty stack")
+	}
+
+	base := empty.Push("a").Push("b")
+	left := base.Push("left")
+	right := base.Push("right")
+
+	cases := []struct {
+		version        *Stack
+		expectedValues string
+	}{
+		{version: empty, expectedValues: "[]"},
+		{version: base, expectedValues: "[a b]"},
+		{version: left, expectedValues: "[a b left]"},
+		{version: right, expectedValues: "[a b right]"},
+	}
+	for i, aCase := range cases {
+		if values := fmt.Sprint(aCase.version.Values()); values != aCase.expectedValues {
+			t.Errorf("Error in case %d. Expected %s, got %s", i, aCase.expectedValues, values)
+		}
+	}
+
+	value, rest, err := left.Pop()
+	if err != nil || value != "left" || rest != base {
+		t.Errorf("Expected Pop to return the shared version below, got %v with error %v", value, err)
+	}
+	if top, _ := left.Peek(); top != "left" || left.Length() != 3 {
+		t.Errorf("Expected Pop not to change the stack")
+	}
+}
+
+func TestEach(t *testing.T) {
+	s := Of(1, 2, 3, 4)
+	visited := []interface{}{}
+	s.Each(func(value interface{}) bool {
+		visited = append(visited, value)
+		return value != 3
+	})
+	if fmt.Sprint(visited) != "[4 3]" {
+		t.Errorf("Expected [4 3], got %v", visited)
+	}
+}
+
+func TestConversion(t *testing.T) {
+	mutable := stack.NewStack()
+	mutable.Push(1)
+	mutable.Push(2)
+
+	persistent := FromStack(mutable)
+	mutable.Push(3)
+	if persistent.Length() != 2 {
+		t.Errorf("Expected the persistent copy not to follow the mutable stack, got length %d", persistent.Length())
+	}
+	if top, _ := persistent.Peek(); top != 2 {
+		t.Errorf("Expected 2 on top, got %v", top)
+	}
+
+	back := persistent.Push(5).ToStack()
+	for _, expected := range []int{5, 2, 1} {
+		if value, err := back.Pop(); err != nil || value != expected {
+			t.Errorf("Expected %d, got %v with error %v", expected, value, err)
+		}
+	}
+	if FromStack(nil) != nil {
+		t.Errorf("Expected a nil mutable stack to convert to the empty stack")
+	}
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	shared := Of()
+	for i := 0; i < 1000; i++ {
+		shared = shared.Push(i)
+	}
+
+	wg := sync.WaitGroup{}
+	for reader := 0; reader < 8; reader++ {
+		wg.Add(1)
+		go func(reader int) {
+			defer wg.Done()
+			own := shared
+			for i := 0; i < 100; i++ {
+				own = own.Push(reader)
+				_, own, _ = own.Pop()
+				_, own, _ = own.Pop()
+			}
+			if own.Length() != 900 || shared.Length() != 1000 {
+				t.Errorf("Reader %d expected lengths 900 and 1000, got %d and %d", reader, own.Length(), shared.Length())
+			}
+		}(reader)
+	}
+	wg.Wait()
+}
+
+func ExampleStack() {
+	base := Of("a", "b")
+	extended := base.Push("c")
+	_, popped, _ := base.Pop()
+
+	fmt.Println(base.Values(), extended.Values(), popped.Values())
+	//Output: [a b] [a b c] [a]
+}
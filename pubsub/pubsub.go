@@ -0,0 +1,388 @@
+//Pubsub is an implementation of a topic-style log with independent subscribers.
+//Every published value gets a consecutive offset and is read by every subscriber, each at its own pace.
+//Values are kept in a chain of elements like in queue.Queue, and dropped from the front by the retention policy.
+//Retention doesn't wait for slow subscribers - they skip ahead to the oldest retained value.
+//Safe to use concurrently.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+//*************** Topic Public Interface ***************
+
+//Position decides where a new subscriber starts reading.
+type Position int
+
+const (
+	//Start at the oldest value still retained.
+	Oldest Position = iota
+	//Start after the newest value, only values published later are read.
+	Latest
+)
+
+//Options configures a Topic. The zero value keeps every value forever.
+type Options struct {
+	//Maximum number of retained values. Zero means no limit.
+	MaxItems int
+	//Maximum age of a retained value. Zero means no limit.
+	MaxAge time.Duration
+	//Source of the current time. time.Now is used when nil.
+	Clock func() time.Time
+}
+
+//Topic is a log of published values read by any number of named subscribers. Goroutine safe.
+type Topic struct {
+	options Options
+	//Oldest retained element and the newest element
+	head *element
+	tail *element
+	//Number of retained elements
+	length     int
+	nextOffset uint64
+	closed     bool
+	//Closed and replaced whenever a value is published, wakes up waiting subscribers
+	changed     chan struct{}
+	subscribers map[string]*Subscriber
+	mutex       sync.Mutex
+}
+
+//Subscriber reads the values of a topic in order. Belongs to a single topic.
+type Subscriber struct {
+	name  string
+	topic *Topic
+	//Last element read, nil if nothing was read yet. The next value is the one after it.
+	cursor *element
+	//Offset of the next value to read
+	offset       uint64
+	unsubscribed bool
+}
+
+//NewTopic initializes an empty Topic. Recommended way of initialization.
+func NewTopic(options Options) *Topic {
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
+	return &Topic{options: options, changed: make(chan struct{}), subscribers: map[string]*Subscriber{}}
+}
+
+//Length returns the number of retained values. Returns 0 on an uninitialized Topic.
+func (t *Topic) Length() int {
+	if t == nil {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.enforceRetention()
+	return t.length
+}
+
+//FirstOffset returns the offset of the oldest retained value.
+//If no value is retained, it equals NextOffset.
+func (t *Topic) FirstOffset() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.enforceRetention()
+	return t.firstOffset()
+}
+
+//NextOffset returns the offset the next published value will get.
+func (t *Topic) NextOffset() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.nextOffset
+}
+
+//Publish appends value to the topic and returns its offset.
+//Returns an error if the topic is closed.
+//Panics on an uninitialized topic.
+func (t *Topic) Publish(value interface{}) (offset uint64, err error) {
+	if t == nil {
+		panic("Topic is nil")
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.closed {
+		return 0, errors.New("Topic is closed")
+	}
+
+	newElem := &element{value: value, offset: t.nextOffset, publishedAt: t.options.Clock()}
+	if t.tail == nil {
+		t.head = newElem
+	} else {
+		t.tail.next = newElem
+	}
+	t.tail = newElem
+	t.length++
+	t.nextOffset++
+	t.enforceRetention()
+
+	close(t.changed)
+	t.changed = make(chan struct{})
+	return newElem.offset, nil
+}
+
+//Subscribe registers a subscriber under a name, starting at the given position.
+//Returns an error if the name is taken or the topic is closed.
+//Panics on an uninitialized topic.
+func (t *Topic) Subscribe(name string, position Position) (*Subscriber, error) {
+	if t == nil {
+		panic("Topic is nil")
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.closed {
+		return nil, errors.New("Topic is closed")
+	}
+	if _, ok := t.subscribers[name]; ok {
+		return nil, errors.New("Subscriber with this name already exists")
+	}
+
+	t.enforceRetention()
+	subscriber := &Subscriber{name: name, topic: t, offset: t.firstOffset()}
+	if position == Latest {
+		subscriber.cursor = t.tail
+		subscriber.offset = t.nextOffset
+	}
+	t.subscribers[name] = subscriber
+	return subscriber, nil
+}
+
+//Subscriber returns the subscriber registered under a name, or nil if there is none.
+func (t *Topic) Subscriber(name string) *Subscriber {
+	if t == nil {
+		return nil
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.subscribers[name]
+}
+
+//Subscribers returns the number of registered subscribers.
+func (t *Topic) Subscribers() int {
+	if t == nil {
+		return 0
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return len(t.subscribers)
+}
+
+//Close stops accepting values. Subscribers can still read every retained value,
+//after that reads return an error instead of waiting. Safe to call more than once.
+func (t *Topic) Close() {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.closed {
+		return
+	}
+	t.closed = true
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+//*************** Subscriber Public Interface ***************
+
+//Name returns the name the subscriber was registered under.
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+//Offset returns the offset of the next value the subscriber will read.
+//Values dropped by retention are skipped, so the next read may return a higher offset.
+func (s *Subscriber) Offset() uint64 {
+	s.topic.mutex.Lock()
+	defer s.topic.mutex.Unlock()
+
+	return s.offset
+}
+
+//Lag returns the number of retained values the subscriber hasn't read yet.
+func (s *Subscriber) Lag() int {
+	s.topic.mutex.Lock()
+	defer s.topic.mutex.Unlock()
+
+	s.topic.enforceRetention()
+	if s.topic.length == 0 {
+		return 0
+	}
+	offset := s.offset
+	if first := s.topic.firstOffset(); offset < first {
+		offset = first
+	}
+	return int(s.topic.nextOffset - offset)
+}
+
+//Read returns the next value and its offset without waiting.
+//If the subscriber has read everything, returns an error.
+func (s *Subscriber) Read() (value interface{}, offset uint64, err error) {
+	s.topic.mutex.Lock()
+	defer s.topic.mutex.Unlock()
+
+	if s.unsubscribed {
+		return nil, 0, errors.New("Subscriber was removed")
+	}
+	next := s.advance()
+	if next == nil {
+		if s.topic.closed {
+			return nil, 0, errors.New("Topic is closed")
+		}
+		return nil, 0, errors.New("No new values")
+	}
+	return next.value, next.offset, nil
+}
+
+//ReadWait returns the next value and its offset, waiting until one is published or the context ends.
+//Returns the context error if the context ends first, an error if the topic is closed and fully read.
+func (s *Subscriber) ReadWait(ctx context.Context) (value interface{}, offset uint64, err error) {
+	for {
+		s.topic.mutex.Lock()
+		if s.unsubscribed {
+			s.topic.mutex.Unlock()
+			return nil, 0, errors.New("Subscriber was removed")
+		}
+		if next := s.advance(); next != nil {
+			s.topic.mutex.Unlock()
+			return next.value, next.offset, nil
+		}
+		if s.topic.closed {
+			s.topic.mutex.Unlock()
+			return nil, 0, errors.New("Topic is closed")
+		}
+		changed := s.topic.changed
+		s.topic.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+//Seek moves the subscriber so the next read returns the value at offset.
+//Offsets older than the oldest retained value start at the oldest one, offsets past the end wait for new values.
+func (s *Subscriber) Seek(offset uint64) {
+	s.topic.mutex.Lock()
+	defer s.topic.mutex.Unlock()
+
+	s.topic.enforceRetention()
+	if offset > s.topic.nextOffset {
+		offset = s.topic.nextOffset
+	}
+	s.offset = offset
+	s.cursor = nil
+	for currentElement := s.topic.head; currentElement != nil && currentElement.offset < offset; currentElement = currentElement.next {
+		s.cursor = currentElement
+	}
+}
+
+//Unsubscribe removes the subscriber from the topic. Further reads return an error and the name can be reused.
+func (s *Subscriber) Unsubscribe() {
+	s.topic.mutex.Lock()
+	defer s.topic.mutex.Unlock()
+
+	if s.unsubscribed {
+		return
+	}
+	s.unsubscribed = true
+	s.cursor = nil
+	delete(s.topic.subscribers, s.name)
+	//Wake up a ReadWait blocked on this subscriber
+	close(s.topic.changed)
+	s.topic.changed = make(chan struct{})
+}
+
+//*************** Topic Internal Structure ***************
+
+type element struct {
+	value       interface{}
+	offset      uint64
+	publishedAt time.Time
+	//Newer element, nil at the tail. Cleared once the element is dropped, so a lagging subscriber doesn't keep newer elements alive.
+	next *element
+}
+
+//firstOffset returns the offset of the head, or the next offset when nothing is retained. No locking.
+func (t *Topic) firstOffset() uint64 {
+	if t.head == nil {
+		return t.nextOffset
+	}
+	return t.head.offset
+}
+
+//enforceRetention drops values from the front that exceed the count or age limit. No locking.
+func (t *Topic) enforceRetention() {
+	for t.options.MaxItems > 0 && t.length > t.options.MaxItems {
+		t.dropHead()
+	}
+	if t.options.MaxAge <= 0 {
+		return
+	}
+	oldestAllowed := t.options.Clock().Add(-t.options.MaxAge)
+	for t.head != nil && t.head.publishedAt.Before(oldestAllowed) {
+		t.dropHead()
+	}
+}
+
+//dropHead removes the oldest element. No locking.
+func (t *Topic) dropHead() {
+	dropped := t.head
+	t.head = dropped.next
+	dropped.next = nil
+	if t.head == nil {
+		t.tail = nil
+	}
+	t.length--
+}
+
+//advance moves the subscriber past the next retained value and returns its element, nil if there is none. No locking.
+func (s *Subscriber) advance() *element {
+	s.topic.enforceRetention()
+
+	var next *element
+	if s.cursor == nil {
+		next = s.topic.head
+	} else {
+		next = s.cursor.next
+	}
+	//Skip values that were dropped while the subscriber wasn't reading, a dropped cursor has no next
+	if next == nil || next.offset < s.topic.firstOffset() {
+		next = s.topic.head
+	}
+	if next == nil || next.offset < s.offset {
+		return nil
+	}
+
+	s.cursor = next
+	s.offset = next.offset + 1
+	return next
+}
@@ -0,0 +1,288 @@
+package pubsub_test
+
+import (
+	"context"
+	. "datatypes/pubsub"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+	"weak"
+)
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func readAll(subscriber *Subscriber) []interface{} {
+	values := []interface{}{}
+	for {
+		value, _, err := subscriber.Read()
+		if err != nil {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+func equalValues(first []interface{}, second []interface{}) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			return false
+		}
+	}
+	return true
+}
+
+//*************** Public Interface Test ***************
+
+func TestIndependentSubscribers(t *testing.T) {
+	topic := NewTopic(Options{})
+	fast, _ := topic.Subscribe("fast", Oldest)
+	slow, _ := topic.Subscribe("slow", Oldest)
+	if _, err := topic.Subscribe("fast", Oldest); err == nil {
+		t.Errorf("Expected an error when reusing a subscriber name")
+	}
+
+	for i := 0; i < 3; i++ {
+		offset, err := topic.Publish(i)
+		if err != nil || offset != uint64(i) {
+			t.Fatalf("Expected offset %d, got %d with error %v", i, offset, err)
+		}
+	}
+
+	if values := readAll(fast); !equalValues(values, []interface{}{0, 1, 2}) {
+		t.Errorf("Fast subscriber read %v", values)
+	}
+	if slow.Lag() != 3 || fast.Lag() != 0 {
+		t.Errorf("Expected lags 3 and 0, got %d and %d", slow.Lag(), fast.Lag())
+	}
+
+	value, offset, err := slow.Read()
+	if err != nil || value != 0 || offset != 0 || slow.Offset() != 1 {
+		t.Errorf("Expected slow subscriber to read 0 at offset 0, got %v at %d with error %v", value, offset, err)
+	}
+
+	late, _ := topic.Subscribe("late", Latest)
+	topic.Publish(3)
+	if values := readAll(late); !equalValues(values, []interface{}{3}) {
+		t.Errorf("Subscriber starting at latest read %v", values)
+	}
+	if values := readAll(slow); !equalValues(values, []interface{}{1, 2, 3}) {
+		t.Errorf("Slow subscriber read %v", values)
+	}
+	if topic.Length() != 4 || topic.Subscribers() != 3 {
+		t.Errorf("Expected 4 values and 3 subscribers, got %d and %d", topic.Length(), topic.Subscribers())
+	}
+}
+
+func TestRetentionByCount(t *testing.T) {
+	topic := NewTopic(Options{MaxItems: 3})
+	subscriber, _ := topic.Subscribe("reader", Oldest)
+	topic.Publish("a")
+	subscriber.Read()
+
+	for _, value := range []string{"b", "c", "d", "e", "f"} {
+		topic.Publish(value)
+	}
+
+	if topic.Length() != 3 || topic.FirstOffset() != 3 || topic.NextOffset() != 6 {
+		t.Errorf("Expected offsets 3 to 5 retained, got length %d, first %d, next %d", topic.Length(), topic.FirstOffset(), topic.NextOffset())
+	}
+	if subscriber.Lag() != 3 {
+		t.Errorf("Expected lag 3, got %d", subscriber.Lag())
+	}
+	value, offset, _ := subscriber.Read()
+	if value != "d" || offset != 3 {
+		t.Errorf("Expected subscriber to skip to d at offset 3, got %v at %d", value, offset)
+	}
+}
+
+func TestRetentionPastLaggingCursor(t *testing.T) {
+	type payload struct {
+		id   int
+		data [64]byte
+	}
+	clock := &manualClock{now: time.Unix(1000, 0)}
+	topic := NewTopic(Options{MaxItems: 2, MaxAge: time.Minute, Clock: clock.Now})
+	subscriber, _ := topic.Subscribe("idle", Oldest)
+	topic.Publish(&payload{id: 0})
+	subscriber.Read()
+
+	//Values published after the idle subscriber's cursor and dropped again must not stay reachable through it
+	dropped := []weak.Pointer[payload]{}
+	for i := 1; i <= 10; i++ {
+		value := &payload{id: i}
+		if i <= 8 {
+			dropped = append(dropped, weak.Make(value))
+		}
+		topic.Publish(value)
+	}
+	runtime.GC()
+	for i, pointer := range dropped {
+		if pointer.Value() != nil {
+			t.Errorf("Expected dropped value %d to be collected", i+1)
+		}
+	}
+
+	value, offset, err := subscriber.Read()
+	if err != nil || value.(*payload).id != 9 || offset != 9 {
+		t.Errorf("Expected subscriber to resume at the retained head 9, got %v at %d with error %v", value, offset, err)
+	}
+
+	//Drop everything, then resume from the next published value
+	clock.now = clock.now.Add(2 * time.Minute)
+	topic.Publish(&payload{id: 11})
+	if value, offset, err := subscriber.Read(); err != nil || value.(*payload).id != 11 || offset != 11 {
+		t.Errorf("Expected subscriber to resume at 11, got %v at %d with error %v", value, offset, err)
+	}
+}
+
+func TestRetentionByAge(t *testing.T) {
+	clock := &manualClock{now: time.Unix(1000, 0)}
+	topic := NewTopic(Options{MaxAge: time.Minute, Clock: clock.Now})
+	subscriber, _ := topic.Subscribe("reader", Oldest)
+
+	topic.Publish("old")
+	clock.now = clock.now.Add(45 * time.Second)
+	topic.Publish("new")
+	clock.now = clock.now.Add(30 * time.Second)
+
+	if values := readAll(subscriber); !equalValues(values, []interface{}{"new"}) {
+		t.Errorf("Expected only the new value, got %v", values)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if topic.Length() != 0 || topic.FirstOffset() != 2 {
+		t.Errorf("Expected every value dropped, got length %d and first offset %d", topic.Length(), topic.FirstOffset())
+	}
+}
+
+func TestSeek(t *testing.T) {
+	topic := NewTopic(Options{MaxItems: 5})
+	subscriber, _ := topic.Subscribe("reader", Latest)
+	for i := 0; i < 8; i++ {
+		topic.Publish(i)
+	}
+
+	cases := []struct {
+		seek          uint64
+		expectedFirst interface{}
+	}{
+		{seek: 5, expectedFirst: 5},
+		{seek: 0, expectedFirst: 3},
+		{seek: 7, expectedFirst: 7},
+		{seek: 100, expectedFirst: nil},
+	}
+	for i, aCase := range cases {
+		subscriber.Seek(aCase.seek)
+		value, _, _ := subscriber.Read()
+		if value != aCase.expectedFirst {
+			t.Errorf("Error in case %d. Expected %v, got %v", i, aCase.expectedFirst, value)
+		}
+	}
+
+	topic.Publish(8)
+	if value, offset, _ := subscriber.Read(); value != 8 || offset != 8 {
+		t.Errorf("Expected 8 after seeking past the end, got %v at %d", value, offset)
+	}
+}
+
+func TestReadWait(t *testing.T) {
+	topic := NewTopic(Options{})
+	subscriber, _ := topic.Subscribe("reader", Oldest)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := subscriber.ReadWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		topic.Publish("hello")
+		topic.Close()
+	}()
+	value, _, err := subscriber.ReadWait(context.Background())
+	if err != nil || value != "hello" {
+		t.Errorf("Expected hello, got %v with error %v", value, err)
+	}
+	if _, _, err := subscriber.ReadWait(context.Background()); err == nil {
+		t.Errorf("Expected an error after the topic was closed and read")
+	}
+	if _, err := topic.Publish("late"); err == nil {
+		t.Errorf("Expected an error when publishing to a closed topic")
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	topic := NewTopic(Options{})
+	subscriber, _ := topic.Subscribe("reader", Oldest)
+
+	done := make(chan error)
+	go func() {
+		_, _, err := subscriber.ReadWait(context.Background())
+		done <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	subscriber.Unsubscribe()
+
+	if err := <-done; err == nil {
+		t.Errorf("Expected blocked read to fail after unsubscribing")
+	}
+	if topic.Subscriber("reader") != nil || topic.Subscribers() != 0 {
+		t.Errorf("Expected subscriber to be removed")
+	}
+	if _, err := topic.Subscribe("reader", Oldest); err != nil {
+		t.Errorf("Expected the name to be reusable, got %s", err.Error())
+	}
+}
+
+func TestConcurrentSubscribers(t *testing.T) {
+	const values = 1000
+	topic := NewTopic(Options{})
+	subscribers := []*Subscriber{}
+	for i := 0; i < 4; i++ {
+		subscriber, _ := topic.Subscribe(fmt.Sprintf("reader-%d", i), Oldest)
+		subscribers = append(subscribers, subscriber)
+	}
+
+	wg := sync.WaitGroup{}
+	for _, subscriber := range subscribers {
+		wg.Add(1)
+		go func(subscriber *Subscriber) {
+			defer wg.Done()
+			for i := 0; i < values; i++ {
+				value, offset, err := subscriber.ReadWait(context.Background())
+				if err != nil || value != i || offset != uint64(i) {
+					t.Errorf("%s expected %d, got %v at %d with error %v", subscriber.Name(), i, value, offset, err)
+					return
+				}
+			}
+		}(subscriber)
+	}
+	for i := 0; i < values; i++ {
+		topic.Publish(i)
+	}
+	wg.Wait()
+}
+
+func Example() {
+	topic := NewTopic(Options{MaxItems: 100})
+	audit, _ := topic.Subscribe("audit", Oldest)
+	billing, _ := topic.Subscribe("billing", Oldest)
+
+	topic.Publish("order created")
+
+	first, _, _ := audit.Read()
+	second, _, _ := billing.Read()
+	fmt.Println(first, "/", second)
+	//Output: order created / order created
+}
@@ -0,0 +1,62 @@
+package queue
+
+import (
+	"datatypes/binarycodec"
+	"errors"
+)
+
+//*************** Binary Encoding ***************
+
+//MarshalBinary encodes the queue in the binarycodec format, elements are encoded with gob.
+//Values are collected under a read lock, so the encoding is a consistent view.
+func (q *Queue) MarshalBinary() ([]byte, error) {
+	return q.MarshalBinaryWith(binarycodec.GobCodec{})
+}
+
+//UnmarshalBinary replaces the content of the queue with values encoded by MarshalBinary.
+func (q *Queue) UnmarshalBinary(data []byte) error {
+	return q.UnmarshalBinaryWith(data, binarycodec.GobCodec{})
+}
+
+//GobEncode implements gob.GobEncoder, so the queue can be a part of gob encoded structures.
+func (q *Queue) GobEncode() ([]byte, error) {
+	return q.MarshalBinary()
+}
+
+//GobDecode implements gob.GobDecoder.
+func (q *Queue) GobDecode(data []byte) error {
+	return q.UnmarshalBinary(data)
+}
+
+//MarshalBinaryWith encodes the queue in the binarycodec format, elements are encoded with the codec.
+//Returns an error on an uninitialized Queue.
+func (q *Queue) MarshalBinaryWith(codec binarycodec.Codec) ([]byte, error) {
+	if q == nil {
+		return nil, errors.New("Queue is nil")
+	}
+
+	q.rwMutex.RLock()
+	values := q.values()
+	q.rwMutex.RUnlock()
+
+	return binarycodec.Encode(binarycodec.KindQueue, values, codec)
+}
+
+//UnmarshalBinaryWith replaces the content of the queue with values decoded by the codec.
+//The queue is left unchanged if decoding fails. Returns an error on an uninitialized Queue.
+func (q *Queue) UnmarshalBinaryWith(data []byte, codec binarycodec.Codec) error {
+	if q == nil {
+		return errors.New("Queue is nil")
+	}
+
+	values, err := binarycodec.Decode(binarycodec.KindQueue, data, codec)
+	if err != nil {
+		return err
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.replaceValues(values)
+	return nil
+}
@@ -0,0 +1,96 @@
+package queue_test
+
+import (
+	"bytes"
+	. "datatypes/queue"
+	"datatypes/stack"
+	"encoding/gob"
+	"testing"
+)
+
+//*************** Binary Encoding Test ***************
+
+func TestBinaryRoundTrip(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		queueInstance  *Queue
+		expectedValues []interface{}
+	}{
+		{queueInstance: emptyQueue, expectedValues: []interface{}{}},
+		{queueInstance: oneElementQueue, expectedValues: []interface{}{0}},
+		{queueInstance: twoElementQueue, expectedValues: []interface{}{"0", "1"}},
+	}
+
+	for i, aCase := range cases {
+		data, err := aCase.queueInstance.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+
+		decodedQueue := NewQueue()
+		decodedQueue.Enqueue("replaced")
+		if err := decodedQueue.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if length := decodedQueue.Length(); length != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedValues), length)
+			continue
+		}
+		for j, expectedValue := range aCase.expectedValues {
+			value, _ := decodedQueue.Dequeue()
+			if value != expectedValue {
+				t.Errorf("Error in case %d, dequeue %d. Expected value %v, got %v", i, j, expectedValue, value)
+			}
+		}
+	}
+
+	//Nil queue can't be encoded or decoded
+	if _, err := nilQueue.MarshalBinary(); err == nil {
+		t.Errorf("Expected an error encoding a nil queue, got no error")
+	}
+	if err := nilQueue.UnmarshalBinary([]byte{}); err == nil {
+		t.Errorf("Expected an error decoding into a nil queue, got no error")
+	}
+}
+
+func TestUnmarshalBinaryWrongKind(t *testing.T) {
+	aStack := stack.NewStack()
+	aStack.Push(1)
+	data, _ := aStack.MarshalBinary()
+
+	aQueue := NewQueue()
+	aQueue.Enqueue("kept")
+	if err := aQueue.UnmarshalBinary(data); err == nil {
+		t.Errorf("Expected an error decoding a stack into a queue, got no error")
+	}
+	if value, _ := aQueue.Peek(); value != "kept" || aQueue.Length() != 1 {
+		t.Errorf("Queue changed after a failed decoding")
+	}
+}
+
+func TestGob(t *testing.T) {
+	type jobs struct {
+		Name    string
+		Pending *Queue
+	}
+
+	original := jobs{Name: "jobs", Pending: NewQueue()}
+	original.Pending.Enqueue("first")
+	original.Pending.Enqueue("second")
+
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(original); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	decoded := jobs{}
+	if err := gob.NewDecoder(&buffer).Decode(&decoded); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	if decoded.Name != "jobs" || decoded.Pending.Length() != 2 {
+		t.Fatalf("Decoded structure is incorrect")
+	}
+	if value, _ := decoded.Pending.Dequeue(); value != "first" {
+		t.Errorf("Expected value first, got %v", value)
+	}
+}
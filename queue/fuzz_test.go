@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"testing"
+)
+
+//*************** Fuzz Test ***************
+
+const (
+	fuzzEnqueue = iota
+	fuzzDequeue
+	fuzzPeek
+	fuzzLength
+	numberOfFuzzOperations
+)
+
+//FuzzQueue decodes the input into a sequence of operations, one byte per operation.
+//Every step is compared against a slice oracle and followed by an invariant check.
+func FuzzQueue(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{fuzzEnqueue, fuzzPeek, fuzzDequeue, fuzzDequeue})
+	f.Add([]byte{fuzzEnqueue, fuzzEnqueue, fuzzDequeue, fuzzEnqueue, fuzzDequeue, fuzzDequeue, fuzzPeek})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		q := NewQueue()
+		oracle := []interface{}{}
+
+		for step, operationByte := range data {
+			switch int(operationByte) % numberOfFuzzOperations {
+			case fuzzEnqueue:
+				q.Enqueue(step)
+				oracle = append(oracle, step)
+			case fuzzDequeue:
+				value, err := q.Dequeue()
+				if len(oracle) == 0 {
+					if err == nil {
+						t.Fatalf("Step %d. Dequeue on an empty queue returned no error", step)
+					}
+					break
+				}
+				if err != nil || value != oracle[0] {
+					t.Fatalf("Step %d. Dequeue returned %v (error: %v), expected %v", step, value, err, oracle[0])
+				}
+				oracle = oracle[1:]
+			case fuzzPeek:
+				value, err := q.Peek()
+				if len(oracle) == 0 {
+					if err == nil {
+						t.Fatalf("Step %d. Peek on an empty queue returned no error", step)
+					}
+					break
+				}
+				if err != nil || value != oracle[0] {
+					t.Fatalf("Step %d. Peek returned %v (error: %v), expected %v", step, value, err, oracle[0])
+				}
+			case fuzzLength:
+			}
+
+			if length := q.Length(); length != len(oracle) {
+				t.Fatalf("Step %d. Expected length %d, got %d", step, len(oracle), length)
+			}
+			if err := q.checkInvariants(); err != nil {
+				t.Fatalf("Step %d. Invariant violated: %s", step, err.Error())
+			}
+		}
+	})
+}
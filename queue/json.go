@@ -0,0 +1,50 @@
+package queue
+
+import (
+	"datatypes/internal/serialize"
+	"encoding/json"
+	"errors"
+)
+
+//*************** JSON Encoding ***************
+
+//MarshalJSON encodes the queue as a JSON array, front of the queue first.
+//Values are collected under a read lock, so the array is a consistent view. Returns null on an uninitialized Queue.
+func (q *Queue) MarshalJSON() ([]byte, error) {
+	if q == nil {
+		return []byte("null"), nil
+	}
+
+	q.rwMutex.RLock()
+	values := q.values()
+	q.rwMutex.RUnlock()
+
+	return json.Marshal(values)
+}
+
+//UnmarshalJSON replaces the content of the queue with the values of a JSON array, front of the queue first.
+//Values are decoded the way encoding/json decodes into interface{}, use UnmarshalJSONWith for typed values.
+//Returns an error on an uninitialized Queue.
+func (q *Queue) UnmarshalJSON(data []byte) error {
+	return q.UnmarshalJSONWith(data, serialize.DecodeInterface)
+}
+
+//UnmarshalJSONWith replaces the content of the queue with the values of a JSON array, front of the queue first.
+//Every array element is passed to decodeElement, which returns the value to store.
+//The queue is left unchanged if decoding fails. Returns an error on an uninitialized Queue.
+func (q *Queue) UnmarshalJSONWith(data []byte, decodeElement func(json.RawMessage) (interface{}, error)) error {
+	if q == nil {
+		return errors.New("Queue is nil")
+	}
+
+	values, err := serialize.DecodeJSONArray(data, decodeElement)
+	if err != nil {
+		return err
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.replaceValues(values)
+	return nil
+}
@@ -0,0 +1,124 @@
+package queue_test
+
+import (
+	. "datatypes/queue"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+//*************** JSON Encoding Test ***************
+
+func TestMarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		queueInstance *Queue
+		expectedJSON  string
+	}{
+		{queueInstance: nilQueue, expectedJSON: "null"},
+		{queueInstance: emptyQueue, expectedJSON: "[]"},
+		{queueInstance: oneElementQueue, expectedJSON: "[0]"},
+		{queueInstance: twoElementQueue, expectedJSON: `["0","1"]`},
+	}
+
+	for i, aCase := range cases {
+		data, err := json.Marshal(aCase.queueInstance)
+		if err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if string(data) != aCase.expectedJSON {
+			t.Errorf("Error in case %d. Expected JSON %s, got %s", i, aCase.expectedJSON, string(data))
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		queueInstance  *Queue
+		data           string
+		expectError    bool
+		expectedValues []interface{}
+	}{
+		{queueInstance: nilQueue, data: "[]", expectError: true, expectedValues: []interface{}{}},
+		{queueInstance: emptyQueue, data: `["a","b"]`, expectError: false, expectedValues: []interface{}{"a", "b"}},
+		//Existing content is replaced
+		{queueInstance: twoElementQueue, data: "[1.5]", expectError: false, expectedValues: []interface{}{1.5}},
+		//Invalid JSON leaves the queue unchanged
+		{queueInstance: oneElementQueue, data: `{"a":1}`, expectError: true, expectedValues: []interface{}{0}},
+	}
+
+	for i, aCase := range cases {
+		err := json.Unmarshal([]byte(aCase.data), aCase.queueInstance)
+		if aCase.expectError && err == nil {
+			t.Errorf("Error in case %d. Expected an error, got no error", i)
+		}
+		if !aCase.expectError && err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if aCase.queueInstance == nil {
+			continue
+		}
+
+		if length := aCase.queueInstance.Length(); length != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedValues), length)
+			continue
+		}
+		for j, expectedValue := range aCase.expectedValues {
+			value, _ := aCase.queueInstance.Dequeue()
+			if value != expectedValue {
+				t.Errorf("Error in case %d, dequeue %d. Expected value %v, got %v", i, j, expectedValue, value)
+			}
+		}
+	}
+}
+
+func TestUnmarshalJSONWith(t *testing.T) {
+	aQueue := NewQueue()
+	decodeInt := func(raw json.RawMessage) (interface{}, error) {
+		return strconv.Atoi(string(raw))
+	}
+
+	err := aQueue.UnmarshalJSONWith([]byte("[3,1,2]"), decodeInt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	for _, expectedValue := range []int{3, 1, 2} {
+		value, _ := aQueue.Dequeue()
+		if value != expectedValue {
+			t.Errorf("Expected typed value %d, got %v", expectedValue, value)
+		}
+	}
+
+	//Decoder errors are returned and the queue is left unchanged
+	aQueue.Enqueue("kept")
+	err = aQueue.UnmarshalJSONWith([]byte(`["not a number"]`), decodeInt)
+	if err == nil {
+		t.Errorf("Expected a decoding error, got no error")
+	}
+	if value, _ := aQueue.Peek(); value != "kept" {
+		t.Errorf("Queue changed after a failed decoding, front value %v", value)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	setVariablesToDefaults()
+	data, err := json.Marshal(veryLongQueue)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	decodedQueue := NewQueue()
+	if err := json.Unmarshal(data, decodedQueue); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if decodedQueue.Length() != LENGTH_OF_LONG_QUEUE {
+		t.Fatalf("Expected length %d, got %d", LENGTH_OF_LONG_QUEUE, decodedQueue.Length())
+	}
+	for i := 0; i < 10; i++ {
+		value, _ := decodedQueue.Dequeue()
+		if value != float64(i) {
+			t.Errorf("Dequeue %d. Expected value %v, got %v", i, float64(i), value)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"datatypes/internal/equality"
+	"errors"
+)
+
+//*************** Lookup Beyond The Front ***************
+
+//PeekAt returns the value at a position counted from the front, 0 being the front, without removing it.
+//Expired values are skipped. If the position is out of range or the queue is nil, returns an error.
+func (q *Queue) PeekAt(position int) (value interface{}, err error) {
+	if q == nil {
+		return nil, errors.New("Queue is nil")
+	}
+	if position < 0 {
+		return nil, errors.New("Position is negative")
+	}
+
+	q.rwMutex.RLock()
+	defer q.rwMutex.RUnlock()
+
+	found := q.find(func(index int, candidate interface{}) bool { return index == position })
+	if found == nil {
+		return nil, errors.New("Position is out of range")
+	}
+	return found.value, nil
+}
+
+//PeekBack returns the value at the back of the queue, the one enqueued last, without removing it.
+//If the queue is empty or nil, returns an error.
+func (q *Queue) PeekBack() (value interface{}, err error) {
+	if q == nil {
+		return nil, errors.New("Queue is nil")
+	}
+
+	q.rwMutex.RLock()
+	defer q.rwMutex.RUnlock()
+
+	backElement := q.backOfTheQueue
+	if backElement == nil {
+		return nil, errors.New("Queue is empty")
+	}
+	if q.ttlCount == 0 || !backElement.isExpired(q.now()) {
+		return backElement.value, nil
+	}
+
+	//The back has expired - the value wanted is the last one still alive
+	live := q.liveLength()
+	if live == 0 {
+		return nil, errors.New("Queue is empty")
+	}
+	lastLive := q.find(func(index int, candidate interface{}) bool { return index == live-1 })
+	return lastLive.value, nil
+}
+
+//Search returns the position of the first value equal to value, counted from the front, or -1 if there is none.
+//Values are compared with ==, values of types that can't be compared never match. Expired values are skipped.
+//Returns -1 on an uninitialized Queue.
+func (q *Queue) Search(value interface{}) int {
+	if q == nil {
+		return -1
+	}
+
+	q.rwMutex.RLock()
+	defer q.rwMutex.RUnlock()
+
+	position := -1
+	q.find(func(index int, candidate interface{}) bool {
+		if equality.Equal(candidate, value) {
+			position = index
+			return true
+		}
+		return false
+	})
+	return position
+}
+
+//Contains reports whether a value equal to value is in the queue. See Search for how values are compared.
+func (q *Queue) Contains(value interface{}) bool {
+	return q.Search(value) >= 0
+}
+
+//*************** Lookup Internal Structure ***************
+
+//find walks the live values from front to back until match returns true, and returns that element.
+//Returns nil if nothing matches. No locking.
+func (q *Queue) find(match func(index int, candidate interface{}) bool) *element {
+	now := q.now()
+	index := 0
+	for currentElement := q.frontOfTheQueue; currentElement != nil; currentElement = currentElement.previousElement {
+		if q.ttlCount > 0 && currentElement.isExpired(now) {
+			continue
+		}
+		if match(index, currentElement.value) {
+			return currentElement
+		}
+		index++
+	}
+	return nil
+}
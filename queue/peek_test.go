@@ -0,0 +1,113 @@
+package queue_test
+
+import (
+	. "datatypes/queue"
+	"sync"
+	"testing"
+	"time"
+)
+
+//*************** Lookup Test ***************
+
+func TestPeekAtAndBack(t *testing.T) {
+	aQueue := NewQueue()
+	if _, err := aQueue.PeekBack(); err == nil {
+		t.Errorf("Expected an error for PeekBack on an empty queue")
+	}
+	for _, value := range []string{"a", "b", "c"} {
+		aQueue.Enqueue(value)
+	}
+
+	cases := []struct {
+		position      int
+		expectedValue interface{}
+		expectError   bool
+	}{
+		{position: 0, expectedValue: "a"},
+		{position: 2, expectedValue: "c"},
+		{position: 3, expectError: true},
+		{position: -1, expectError: true},
+	}
+	for i, aCase := range cases {
+		value, err := aQueue.PeekAt(aCase.position)
+		if (err != nil) != aCase.expectError || value != aCase.expectedValue {
+			t.Errorf("Error in case %d. Expected %v, got %v with error %v", i, aCase.expectedValue, value, err)
+		}
+	}
+	if back, _ := aQueue.PeekBack(); back != "c" || aQueue.Length() != 3 {
+		t.Errorf("Expected c at the back and nothing removed, got %v and length %d", back, aQueue.Length())
+	}
+}
+
+func TestSearch(t *testing.T) {
+	aQueue := NewQueue()
+	aQueue.Enqueue(1)
+	aQueue.Enqueue([]int{1, 2})
+	aQueue.Enqueue("two")
+	aQueue.Enqueue("two")
+
+	cases := []struct {
+		value            interface{}
+		expectedPosition int
+	}{
+		{value: 1, expectedPosition: 0},
+		{value: "two", expectedPosition: 2},
+		{value: int64(1), expectedPosition: -1},
+		{value: []int{1, 2}, expectedPosition: -1},
+		{value: nil, expectedPosition: -1},
+	}
+	for i, aCase := range cases {
+		if position := aQueue.Search(aCase.value); position != aCase.expectedPosition {
+			t.Errorf("Error in case %d. Expected position %d, got %d", i, aCase.expectedPosition, position)
+		}
+		if aQueue.Contains(aCase.value) != (aCase.expectedPosition >= 0) {
+			t.Errorf("Error in case %d. Contains doesn't agree with Search", i)
+		}
+	}
+}
+
+func TestLookupSkipsExpired(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aQueue := NewQueue()
+	aQueue.SetClock(clock.Now)
+	aQueue.EnqueueWithTTL("short", time.Second)
+	aQueue.Enqueue("kept")
+	aQueue.EnqueueWithTTL("back", time.Second)
+	clock.Advance(2 * time.Second)
+
+	if value, _ := aQueue.PeekAt(0); value != "kept" {
+		t.Errorf("Expected kept at the front, got %v", value)
+	}
+	if back, _ := aQueue.PeekBack(); back != "kept" {
+		t.Errorf("Expected kept at the back, got %v", back)
+	}
+	if aQueue.Contains("short") {
+		t.Errorf("Expected expired values not to be found")
+	}
+}
+
+func TestLookupConcurrency(t *testing.T) {
+	aQueue := NewQueue()
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(2)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				aQueue.Enqueue(worker*1000 + i)
+			}
+		}(worker)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				aQueue.PeekAt(i % 10)
+				aQueue.PeekBack()
+				aQueue.Contains(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if !aQueue.Contains(3499) {
+		t.Errorf("Expected 3499 to be in the queue")
+	}
+}
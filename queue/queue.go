@@ -7,6 +7,7 @@ package queue
 import (
 	"errors"
 	"sync"
+	"time"
 )
 
 //Make runtime asserts fatal
@@ -22,6 +23,13 @@ type Queue struct {
 	frontOfTheQueue *element
 	backOfTheQueue  *element
 	rwMutex         sync.RWMutex
+
+	//Expiry settings, see ttl.go
+	defaultTTL   time.Duration
+	clock        func() time.Time
+	onExpire     func(value interface{})
+	ttlCount     int
+	expiredCount int
 }
 
 //NewQueue initializes an empty Queue. Recommended way of initialization.
@@ -38,8 +46,8 @@ func (q *Queue) Length() int {
 	q.rwMutex.RLock()
 	defer q.rwMutex.RUnlock()
 
-	//Call internal function to obtain the length value
-	return q.lengthValue()
+	//Call internal function to obtain the length value, expired values are not counted
+	return q.liveLength()
 }
 
 //Peek returns the value at the front of the queue without removing it.
@@ -50,8 +58,56 @@ func (q *Queue) Peek() (value interface{}, err error) {
 	}
 
 	q.rwMutex.RLock()
-	defer q.rwMutex.RUnlock()
+	if !q.frontIsExpired() {
+		defer q.rwMutex.RUnlock()
+		return q.peekValue()
+	}
+	q.rwMutex.RUnlock()
+
+	//Expired values at the front have to be dropped, which needs the write lock
+	q.rwMutex.Lock()
+	notifyExpired := q.dropExpiredFront()
+	value, err = q.peekValue()
+	q.rwMutex.Unlock()
+
+	notifyExpired()
+	return value, err
+}
+
+//Enqueue adds value to back of the queue. The value expires after the default TTL, if one is set.
+//Panics on an uninitialized queue.
+func (q *Queue) Enqueue(value interface{}) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.enqueueValue(value, q.defaultTTL)
+}
 
+//Dequeue removes the value from the front of the queue. Expired values are dropped on the way.
+//If queue is empty, returns error.
+//Panics on an uninitialized queue.
+func (q *Queue) Dequeue() (valueRemoved interface{}, err error) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	notifyExpired := q.dropExpiredFront()
+	valueRemoved, err = q.dequeueValue()
+	q.rwMutex.Unlock()
+
+	notifyExpired()
+	return valueRemoved, err
+}
+
+//*************** Queue Internal Structure ***************
+
+//Internal peek method with no locking.
+func (q *Queue) peekValue() (value interface{}, err error) {
 	length := q.lengthValue()
 	//If queue is empty - Peek returns an eror
 	if length == 0 {
@@ -70,17 +126,10 @@ func (q *Queue) Peek() (value interface{}, err error) {
 	return frontElement.value, nil
 }
 
-//Enqueue adds value to back of the queue.
-//Panics on an uninitialized queue.
-func (q *Queue) Enqueue(value interface{}) {
-	if q == nil {
-		panic("Queue is nil")
-	}
-
-	q.rwMutex.Lock()
-	defer q.rwMutex.Unlock()
-
+//Internal enqueue method with no locking. A zero ttl means the value never expires.
+func (q *Queue) enqueueValue(value interface{}, ttl time.Duration) {
 	newElem := newElement(value, nil)
+	q.setExpiry(newElem, ttl)
 	length := q.lengthValue()
 
 	//If the length is zero - set the new element as front and back of the queue.
@@ -98,16 +147,8 @@ func (q *Queue) Enqueue(value interface{}) {
 	q.changeLength(1)
 }
 
-//Dequeue removes the value from the front of the queue. If queue is empty, returns error.
-//Panics on an uninitialized queue.
-func (q *Queue) Dequeue() (valueRemoved interface{}, err error) {
-	if q == nil {
-		panic("Queue is nil")
-	}
-
-	q.rwMutex.Lock()
-	defer q.rwMutex.Unlock()
-
+//Internal dequeue method with no locking.
+func (q *Queue) dequeueValue() (valueRemoved interface{}, err error) {
 	length := q.lengthValue()
 
 	//If queue is empty - return error
@@ -125,6 +166,7 @@ func (q *Queue) Dequeue() (valueRemoved interface{}, err error) {
 		q.frontOfTheQueue = nil
 		q.backOfTheQueue = nil
 		q.changeLength(-1)
+		q.clearExpiry(currentFrontElement)
 		return currentFrontElement.value, nil
 	}
 
@@ -137,14 +179,15 @@ func (q *Queue) Dequeue() (valueRemoved interface{}, err error) {
 
 	q.frontOfTheQueue = currentFrontElement.previousElement
 	q.changeLength(-1)
+	q.clearExpiry(currentFrontElement)
 	return currentFrontElement.value, nil
 }
 
-//*************** Queue Internal Structure ***************
-
 type element struct {
 	value           interface{}
 	previousElement *element
+	//Zero if the value never expires
+	expiresAt time.Time
 }
 
 func newElement(value interface{}, previousElement *element) *element {
@@ -163,3 +206,71 @@ func (q *Queue) changeLength(delta int) {
 		panic("Queue has negative length")
 	}
 }
+
+//values returns all values from front to back. No locking.
+//Expired values are left out.
+func (q *Queue) values() []interface{} {
+	now := q.now()
+	values := make([]interface{}, 0, q.length)
+	for currentElement := q.frontOfTheQueue; currentElement != nil; currentElement = currentElement.previousElement {
+		if currentElement.isExpired(now) {
+			continue
+		}
+		values = append(values, currentElement.value)
+	}
+	return values
+}
+
+//replaceValues discards the current content and enqueues values front to back. No locking.
+func (q *Queue) replaceValues(values []interface{}) {
+	q.frontOfTheQueue = nil
+	q.backOfTheQueue = nil
+	q.length = 0
+	q.ttlCount = 0
+	for _, value := range values {
+		newElem := newElement(value, nil)
+		if q.backOfTheQueue == nil {
+			q.frontOfTheQueue = newElem
+		} else {
+			q.backOfTheQueue.previousElement = newElem
+		}
+		q.backOfTheQueue = newElem
+		q.changeLength(1)
+	}
+}
+
+//checkInvariants walks the whole queue and verifies the internal structure. No locking.
+func (q *Queue) checkInvariants() error {
+	if q.length < 0 {
+		return errors.New("Length is negative")
+	}
+	if q.length == 0 {
+		if q.frontOfTheQueue != nil || q.backOfTheQueue != nil {
+			return errors.New("Queue is empty, but front or back element is not nil")
+		}
+		return nil
+	}
+	if q.frontOfTheQueue == nil || q.backOfTheQueue == nil {
+		return errors.New("Queue is not empty, but front or back element is nil")
+	}
+	if q.backOfTheQueue.previousElement != nil {
+		return errors.New("Back element points to another element")
+	}
+
+	count := 0
+	lastElement := q.frontOfTheQueue
+	for currentElement := q.frontOfTheQueue; currentElement != nil; currentElement = currentElement.previousElement {
+		count++
+		if count > q.length {
+			return errors.New("More elements in the chain than the recorded length")
+		}
+		lastElement = currentElement
+	}
+	if count != q.length {
+		return errors.New("Fewer elements in the chain than the recorded length")
+	}
+	if lastElement != q.backOfTheQueue {
+		return errors.New("Chain does not end at the back element")
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package queue_test
 
 import (
+	"datatypes/linearizability"
 	. "datatypes/queue"
 	"fmt"
 	"sync"
@@ -273,3 +274,104 @@ func bombardQueue(queue *Queue, wg *sync.WaitGroup) {
 	time.Sleep(time.Microsecond)
 	wg.Done()
 }
+
+//TestLinearizability records concurrent operations on a Queue and checks them against a sequential FIFO model.
+func TestLinearizability(t *testing.T) {
+	aQueue := NewQueue()
+	recorder := linearizability.NewRecorder()
+	var wg sync.WaitGroup
+
+	for client := 0; client < 4; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				linearizability.RecordQueue(recorder, client, aQueue, linearizability.Input{Op: linearizability.Enqueue, Value: fmt.Sprintf("%d-%d", client, i)})
+				linearizability.RecordQueue(recorder, client, aQueue, linearizability.Input{Op: linearizability.Peek})
+				linearizability.RecordQueue(recorder, client, aQueue, linearizability.Input{Op: linearizability.Dequeue})
+				linearizability.RecordQueue(recorder, client, aQueue, linearizability.Input{Op: linearizability.Length})
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	if !linearizability.CheckOperations(linearizability.QueueModel, recorder.History()) {
+		t.Errorf("Concurrent Queue history is not linearizable")
+	}
+}
+
+//*************** Benchmarks ***************
+
+func BenchmarkEnqueue(b *testing.B) {
+	aQueue := NewQueue()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aQueue.Enqueue(i)
+	}
+}
+
+func BenchmarkDequeue(b *testing.B) {
+	aQueue := NewQueue()
+	for i := 0; i < b.N; i++ {
+		aQueue.Enqueue(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aQueue.Dequeue()
+	}
+}
+
+func BenchmarkEnqueueDequeue(b *testing.B) {
+	aQueue := NewQueue()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aQueue.Enqueue(i)
+		aQueue.Dequeue()
+	}
+}
+
+func BenchmarkPeek(b *testing.B) {
+	aQueue := NewQueue()
+	aQueue.Enqueue(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aQueue.Peek()
+	}
+}
+
+func BenchmarkLength(b *testing.B) {
+	aQueue := NewQueue()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aQueue.Length()
+	}
+}
+
+func BenchmarkParallelEnqueueDequeue(b *testing.B) {
+	aQueue := NewQueue()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			aQueue.Enqueue(0)
+			aQueue.Dequeue()
+		}
+	})
+}
+
+func BenchmarkParallelPeek(b *testing.B) {
+	aQueue := NewQueue()
+	aQueue.Enqueue(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			aQueue.Peek()
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"datatypes/binarycodec"
+	"datatypes/internal/serialize"
+	"errors"
+	"io"
+)
+
+//*************** Snapshots ***************
+
+//Snapshot is an immutable, point-in-time view of a Queue. Safe to use concurrently.
+type Snapshot struct {
+	values []interface{}
+}
+
+//Snapshot captures the current content of the queue.
+//Values are copied under a read lock, so concurrent writers are blocked only for the copy.
+//Returns an empty snapshot on an uninitialized Queue.
+func (q *Queue) Snapshot() *Snapshot {
+	if q == nil {
+		return &Snapshot{values: []interface{}{}}
+	}
+
+	q.rwMutex.RLock()
+	defer q.rwMutex.RUnlock()
+
+	return &Snapshot{values: q.values()}
+}
+
+//Restore replaces the content of the queue with the content of the snapshot.
+//Returns an error on an uninitialized Queue or a nil snapshot.
+func (q *Queue) Restore(snapshot *Snapshot) error {
+	if q == nil {
+		return errors.New("Queue is nil")
+	}
+	if snapshot == nil {
+		return errors.New("Snapshot is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.replaceValues(snapshot.values)
+	return nil
+}
+
+//Load replaces the content of the queue with a snapshot read from the reader.
+//Reads everything up to EOF, the data has to be written by Snapshot.WriteTo or Snapshot.WriteFile.
+func (q *Queue) Load(reader io.Reader) error {
+	if q == nil {
+		return errors.New("Queue is nil")
+	}
+
+	return serialize.Load(reader, q)
+}
+
+//Length returns the number of values in the snapshot.
+func (snapshot *Snapshot) Length() int {
+	if snapshot == nil {
+		return 0
+	}
+	return len(snapshot.values)
+}
+
+//Values returns a copy of the values in the snapshot, front of the queue first.
+func (snapshot *Snapshot) Values() []interface{} {
+	if snapshot == nil {
+		return []interface{}{}
+	}
+	return serialize.Copy(snapshot.values)
+}
+
+//MarshalBinary encodes the snapshot in the same format as Queue.MarshalBinary.
+func (snapshot *Snapshot) MarshalBinary() ([]byte, error) {
+	return binarycodec.Encode(binarycodec.KindQueue, snapshot.Values(), binarycodec.GobCodec{})
+}
+
+//WriteTo writes the encoded snapshot to the writer. Implements io.WriterTo.
+func (snapshot *Snapshot) WriteTo(writer io.Writer) (int64, error) {
+	return serialize.WriteTo(writer, snapshot)
+}
+
+//WriteFile atomically replaces the file at path with the encoded snapshot. Suitable for checkpoint files.
+func (snapshot *Snapshot) WriteFile(path string) error {
+	return serialize.WriteFile(path, snapshot)
+}
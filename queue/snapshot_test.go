@@ -0,0 +1,120 @@
+package queue_test
+
+import (
+	"bytes"
+	. "datatypes/queue"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+//*************** Snapshot Test ***************
+
+func TestSnapshot(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		queueInstance  *Queue
+		expectedValues []interface{}
+	}{
+		{queueInstance: nilQueue, expectedValues: []interface{}{}},
+		{queueInstance: emptyQueue, expectedValues: []interface{}{}},
+		{queueInstance: twoElementQueue, expectedValues: []interface{}{"0", "1"}},
+	}
+
+	for i, aCase := range cases {
+		snapshot := aCase.queueInstance.Snapshot()
+		if aCase.queueInstance != nil {
+			//Later changes don't affect the snapshot
+			aCase.queueInstance.Enqueue("later")
+			aCase.queueInstance.Dequeue()
+		}
+
+		if snapshot.Length() != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedValues), snapshot.Length())
+		}
+		if fmt.Sprint(snapshot.Values()) != fmt.Sprint(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected values %v, got %v", i, aCase.expectedValues, snapshot.Values())
+		}
+	}
+}
+
+func TestRestore(t *testing.T) {
+	setVariablesToDefaults()
+	snapshot := twoElementQueue.Snapshot()
+
+	//Values taken out of the restored queue don't change the snapshot
+	restoredQueue := NewQueue()
+	restoredQueue.Enqueue("replaced")
+	if err := restoredQueue.Restore(snapshot); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := restoredQueue.Dequeue(); value != "0" {
+		t.Errorf("Expected value 0, got %v", value)
+	}
+	if snapshot.Length() != 2 || restoredQueue.Length() != 1 {
+		t.Errorf("Snapshot and restored queue share state")
+	}
+
+	if err := restoredQueue.Restore(nil); err == nil {
+		t.Errorf("Expected an error restoring a nil snapshot, got no error")
+	}
+	if err := nilQueue.Restore(snapshot); err == nil {
+		t.Errorf("Expected an error restoring into a nil queue, got no error")
+	}
+}
+
+func TestSnapshotFile(t *testing.T) {
+	setVariablesToDefaults()
+	path := filepath.Join(t.TempDir(), "queue.checkpoint")
+	if err := twoElementQueue.Snapshot().WriteFile(path); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	defer file.Close()
+
+	loadedQueue := NewQueue()
+	if err := loadedQueue.Load(file); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := loadedQueue.Peek(); value != "0" || loadedQueue.Length() != 2 {
+		t.Errorf("Loaded queue is incorrect, front value %v, length %d", value, loadedQueue.Length())
+	}
+
+	if err := loadedQueue.Load(bytes.NewReader([]byte("garbage"))); err == nil {
+		t.Errorf("Expected an error loading garbage, got no error")
+	}
+}
+
+//TestConcurrentSnapshot takes snapshots while other goroutines modify the queue.
+//Every snapshot has to be a sequence of consecutive values. Run with `go test -race`.
+func TestConcurrentSnapshot(t *testing.T) {
+	aQueue := NewQueue()
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			aQueue.Enqueue(i)
+			if i%3 == 0 {
+				aQueue.Dequeue()
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		values := aQueue.Snapshot().Values()
+		for j := 1; j < len(values); j++ {
+			if values[j].(int) != values[j-1].(int)+1 {
+				t.Fatalf("Snapshot is not consistent: %v", values)
+			}
+		}
+	}
+	wg.Wait()
+}
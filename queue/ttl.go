@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"time"
+)
+
+//*************** Value Expiry ***************
+
+//SetDefaultTTL sets how long values added by Enqueue stay in the queue. Zero, the default, disables expiry.
+//Values already in the queue keep their expiry time.
+//Panics on an uninitialized queue.
+func (q *Queue) SetDefaultTTL(ttl time.Duration) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.defaultTTL = ttl
+}
+
+//SetClock replaces the source of the current time used for expiry. Passing nil restores time.Now.
+//Panics on an uninitialized queue.
+func (q *Queue) SetClock(clock func() time.Time) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.clock = clock
+}
+
+//SetExpiryCallback registers a function called with every expired value the queue drops.
+//The callback runs after the queue is unlocked, so it may use the queue. Passing nil removes the callback.
+//Panics on an uninitialized queue.
+func (q *Queue) SetExpiryCallback(callback func(value interface{})) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.onExpire = callback
+}
+
+//EnqueueWithTTL adds value to back of the queue. The value is dropped once the ttl passes.
+//A ttl of zero or less means the value never expires.
+//Panics on an uninitialized queue.
+func (q *Queue) EnqueueWithTTL(value interface{}, ttl time.Duration) {
+	if q == nil {
+		panic("Queue is nil")
+	}
+
+	q.rwMutex.Lock()
+	defer q.rwMutex.Unlock()
+
+	q.enqueueValue(value, ttl)
+}
+
+//ExpiredCount returns the number of expired values dropped so far.
+//Values are dropped when they reach the front of the queue. Returns 0 on an uninitialized Queue.
+func (q *Queue) ExpiredCount() int {
+	if q == nil {
+		return 0
+	}
+
+	q.rwMutex.RLock()
+	defer q.rwMutex.RUnlock()
+
+	return q.expiredCount
+}
+
+//*************** Value Expiry Internal Structure ***************
+
+func (q *Queue) now() time.Time {
+	if q.clock == nil {
+		return time.Now()
+	}
+	return q.clock()
+}
+
+func (el *element) isExpired(now time.Time) bool {
+	return !el.expiresAt.IsZero() && !now.Before(el.expiresAt)
+}
+
+//setExpiry sets the expiry time of a new element. No locking.
+func (q *Queue) setExpiry(el *element, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	el.expiresAt = q.now().Add(ttl)
+	q.ttlCount++
+}
+
+//clearExpiry updates the number of expiring elements after an element is removed. No locking.
+func (q *Queue) clearExpiry(el *element) {
+	if el.expiresAt.IsZero() {
+		return
+	}
+	q.ttlCount--
+	if q.ttlCount < 0 && panic_on_internal_inconsistencies {
+		panic("Queue has a negative number of expiring values")
+	}
+}
+
+//frontIsExpired reports whether the front value has expired. No locking.
+func (q *Queue) frontIsExpired() bool {
+	if q.ttlCount == 0 || q.frontOfTheQueue == nil {
+		return false
+	}
+	return q.frontOfTheQueue.isExpired(q.now())
+}
+
+//liveLength returns the number of values that haven't expired. Walks the queue only if some values can expire. No locking.
+func (q *Queue) liveLength() int {
+	if q.ttlCount == 0 {
+		return q.lengthValue()
+	}
+
+	now := q.now()
+	length := 0
+	for currentElement := q.frontOfTheQueue; currentElement != nil; currentElement = currentElement.previousElement {
+		if !currentElement.isExpired(now) {
+			length++
+		}
+	}
+	return length
+}
+
+//dropExpiredFront removes expired values from the front of the queue. No locking.
+//Returns a function reporting the dropped values to the expiry callback, to be called once the queue is unlocked.
+func (q *Queue) dropExpiredFront() (notifyExpired func()) {
+	var expired []interface{}
+	for q.frontIsExpired() {
+		value, err := q.dequeueValue()
+		if err != nil {
+			if panic_on_internal_inconsistencies {
+				panic("Failed to drop an expired front value")
+			}
+			break
+		}
+		expired = append(expired, value)
+		q.expiredCount++
+	}
+
+	callback := q.onExpire
+	if callback == nil || len(expired) == 0 {
+		return noExpiredValues
+	}
+	return func() {
+		for _, value := range expired {
+			callback(value)
+		}
+	}
+}
+
+func noExpiredValues() {}
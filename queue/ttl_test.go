@@ -0,0 +1,120 @@
+package queue_test
+
+import (
+	. "datatypes/queue"
+	"sync"
+	"testing"
+	"time"
+)
+
+//manualClock is advanced by the tests.
+type manualClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+//*************** Value Expiry Test ***************
+
+func TestEnqueueWithTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aQueue := NewQueue()
+	aQueue.SetClock(clock.Now)
+	expiredValues := []interface{}{}
+	aQueue.SetExpiryCallback(func(value interface{}) {
+		expiredValues = append(expiredValues, value)
+	})
+
+	aQueue.EnqueueWithTTL("short", time.Second)
+	aQueue.Enqueue("forever")
+	aQueue.EnqueueWithTTL("long", time.Minute)
+
+	cases := []struct {
+		advance         time.Duration
+		expectedLength  int
+		expectedFront   interface{}
+		expectedExpired int
+	}{
+		{0, 3, "short", 0},
+		//Front value expires and is dropped by Peek
+		{time.Second, 2, "forever", 1},
+		//Values that expire behind the front are not counted, but stay until they reach the front
+		{time.Minute, 1, "forever", 1},
+	}
+
+	for i, aCase := range cases {
+		clock.Advance(aCase.advance)
+		if length := aQueue.Length(); length != aCase.expectedLength {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, aCase.expectedLength, length)
+		}
+		if value, err := aQueue.Peek(); err != nil || value != aCase.expectedFront {
+			t.Errorf("Error in case %d. Expected front value %v, got %v (error: %v)", i, aCase.expectedFront, value, err)
+		}
+		if aQueue.ExpiredCount() != aCase.expectedExpired {
+			t.Errorf("Error in case %d. Expected %d expired values, got %d", i, aCase.expectedExpired, aQueue.ExpiredCount())
+		}
+	}
+
+	//Dequeue returns the live value and drops the expired one behind it on the next call
+	if value, _ := aQueue.Dequeue(); value != "forever" {
+		t.Errorf("Expected value forever, got %v", value)
+	}
+	if _, err := aQueue.Dequeue(); err == nil {
+		t.Errorf("Expected an error dequeuing only expired values, got no error")
+	}
+	if len(expiredValues) != 2 || expiredValues[0] != "short" || expiredValues[1] != "long" {
+		t.Errorf("Unexpected values reported to the callback: %v", expiredValues)
+	}
+}
+
+func TestDefaultTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aQueue := NewQueue()
+	aQueue.SetClock(clock.Now)
+
+	aQueue.Enqueue("before")
+	aQueue.SetDefaultTTL(time.Second)
+	aQueue.Enqueue("after")
+	aQueue.EnqueueWithTTL("explicit", 0)
+	clock.Advance(time.Second)
+
+	if aQueue.Length() != 2 {
+		t.Errorf("Expected length 2, got %d", aQueue.Length())
+	}
+	if snapshot := aQueue.Snapshot().Values(); len(snapshot) != 2 || snapshot[1] != "explicit" {
+		t.Errorf("Expired values should be left out of snapshots, got %v", snapshot)
+	}
+	for _, expectedValue := range []interface{}{"before", "explicit"} {
+		if value, _ := aQueue.Dequeue(); value != expectedValue {
+			t.Errorf("Expected value %v, got %v", expectedValue, value)
+		}
+	}
+	if aQueue.ExpiredCount() != 1 {
+		t.Errorf("Expected 1 expired value, got %d", aQueue.ExpiredCount())
+	}
+}
+
+func TestExpiryCallbackCanUseQueue(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aQueue := NewQueue()
+	aQueue.SetClock(clock.Now)
+	//Callback runs without the lock held, so it can inspect the queue
+	aQueue.SetExpiryCallback(func(value interface{}) {
+		aQueue.Length()
+	})
+
+	aQueue.EnqueueWithTTL("value", time.Millisecond)
+	clock.Advance(time.Second)
+	aQueue.Dequeue()
+}
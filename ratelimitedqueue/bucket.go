@@ -0,0 +1,82 @@
+package ratelimitedqueue
+
+import (
+	"math"
+	"time"
+)
+
+//*************** Token Bucket ***************
+
+//bucket is a token bucket. Tokens are refilled lazily from the time passed since the last update. No locking.
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+//newBucket returns a full bucket.
+func newBucket(limit Limit, now time.Time) *bucket {
+	limit = normalizeLimit(limit)
+	return &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+}
+
+func normalizeLimit(limit Limit) Limit {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return limit
+}
+
+func (b *bucket) unlimited() bool {
+	return b.limit.Rate <= 0
+}
+
+//refill adds the tokens earned since the last update, up to the burst.
+func (b *bucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+		b.last = now
+	}
+}
+
+//wait returns how long until a token is available, zero if one is available now.
+func (b *bucket) wait(now time.Time) time.Duration {
+	if b.unlimited() {
+		return 0
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	wait := time.Duration(math.Ceil((1 - b.tokens) / b.limit.Rate * float64(time.Second)))
+	if wait <= 0 {
+		wait = 1
+	}
+	return wait
+}
+
+//take spends a token. wait has to be called first.
+func (b *bucket) take() {
+	if b.unlimited() {
+		return
+	}
+	b.tokens--
+}
+
+func (b *bucket) isFull(now time.Time) bool {
+	if b.unlimited() {
+		return true
+	}
+	b.refill(now)
+	return b.tokens >= float64(b.limit.Burst)
+}
+
+//setLimit switches to a new limit, keeping the tokens earned under the old one up to the new burst.
+func (b *bucket) setLimit(limit Limit, now time.Time) {
+	if !b.unlimited() {
+		b.refill(now)
+	}
+	b.last = now
+	b.limit = normalizeLimit(limit)
+	b.tokens = math.Min(b.tokens, float64(b.limit.Burst))
+}
@@ -0,0 +1,350 @@
+//Ratelimitedqueue wraps a queue.Queue and limits how fast values can be dequeued.
+//The limit is a token bucket: a rate of values per second with a burst allowance.
+//With a key function, every key also gets its own bucket. Values of a key that is out of tokens are set aside,
+//so they don't hold back other keys: the oldest value of a key that may continue is dequeued next.
+//Values of the same key keep their FIFO order.
+//Rates can be changed at any time.
+//Safe to use concurrently.
+package ratelimitedqueue
+
+import (
+	"context"
+	"datatypes/queue"
+	"errors"
+	"sync"
+	"time"
+)
+
+//*************** Rate Limited Queue Public Interface ***************
+
+//Default values used for zero fields of Options.
+const (
+	DefaultPollInterval = 10 * time.Millisecond
+)
+
+//Clock is the source of time used by a RateLimitedQueue. Can be replaced in tests.
+type Clock interface {
+	Now() time.Time
+	After(duration time.Duration) <-chan time.Time
+}
+
+//Limit is a token bucket rate. A Rate of zero or below means no limit.
+type Limit struct {
+	//Values per second
+	Rate float64
+	//Number of values that can be dequeued at once after an idle period. Values below 1 mean 1.
+	Burst int
+}
+
+//Options configures a RateLimitedQueue. The zero value doesn't limit anything.
+type Options struct {
+	//Limit shared by all values.
+	Limit Limit
+	//Returns the key of a value. Keys have to be comparable. No per-key limits when nil.
+	KeyFunc func(value interface{}) interface{}
+	//Limit applied to every key separately.
+	KeyLimit Limit
+	//How often a waiting DequeueWait checks for values enqueued directly into the wrapped queue.
+	PollInterval time.Duration
+	//Source of time. The system clock is used when nil.
+	Clock Clock
+}
+
+//RateLimitedQueue is a queue.Queue with a rate-limited Dequeue. Goroutine safe.
+type RateLimitedQueue struct {
+	values   *queue.Queue
+	options  Options
+	global   *bucket
+	keyed    map[interface{}]*bucket
+	keyLimit Limit
+	//Values taken out of the wrapped queue while their key was limited, by key
+	held      map[interface{}]*queue.Queue
+	heldCount int
+	//Order of the next held value
+	nextSeq uint64
+	//Dequeues since the last sweep of idle key buckets
+	sinceSweep int
+	//Closed and replaced whenever a value is enqueued or a limit changes, wakes up waiting consumers
+	changed chan struct{}
+	mutex   sync.Mutex
+}
+
+//NewRateLimitedQueue wraps a queue. A new queue is created when values is nil.
+//Values should only be dequeued through the wrapper, otherwise the limits don't hold.
+func NewRateLimitedQueue(values *queue.Queue, options Options) *RateLimitedQueue {
+	if values == nil {
+		values = queue.NewQueue()
+	}
+	if options.Clock == nil {
+		options.Clock = systemClock{}
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	now := options.Clock.Now()
+	return &RateLimitedQueue{
+		values:   values,
+		options:  options,
+		global:   newBucket(options.Limit, now),
+		keyed:    map[interface{}]*bucket{},
+		held:     map[interface{}]*queue.Queue{},
+		keyLimit: options.KeyLimit,
+		changed:  make(chan struct{}),
+	}
+}
+
+//Length returns the number of values in the wrapped queue, plus the values set aside for limited keys.
+//Returns 0 on an uninitialized RateLimitedQueue.
+func (rq *RateLimitedQueue) Length() int {
+	if rq == nil {
+		return 0
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	return rq.values.Length() + rq.heldCount
+}
+
+//Enqueue adds value to back of the queue. Enqueue is never limited.
+//Panics on an uninitialized queue.
+func (rq *RateLimitedQueue) Enqueue(value interface{}) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.values.Enqueue(value)
+	rq.notify()
+}
+
+//SetLimit changes the shared limit. Tokens saved up so far are kept, up to the new burst.
+//Panics on an uninitialized queue.
+func (rq *RateLimitedQueue) SetLimit(limit Limit) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.global.setLimit(limit, rq.options.Clock.Now())
+	rq.notify()
+}
+
+//SetKeyLimit changes the limit applied to every key.
+//Panics on an uninitialized queue.
+func (rq *RateLimitedQueue) SetKeyLimit(limit Limit) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	now := rq.options.Clock.Now()
+	rq.keyLimit = limit
+	for _, keyBucket := range rq.keyed {
+		keyBucket.setLimit(limit, now)
+	}
+	rq.notify()
+}
+
+//TryDequeue removes the next value the limits allow, without waiting.
+//That is the front value, unless its key is limited, then the oldest value of a key that isn't.
+//If the queue is empty or the limit is exhausted, returns an error.
+//Panics on an uninitialized queue.
+func (rq *RateLimitedQueue) TryDequeue() (valueRemoved interface{}, err error) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	valueRemoved, wait, err := rq.dequeueValue()
+	if err == nil && wait > 0 {
+		return nil, errors.New("Rate limit exceeded")
+	}
+	return valueRemoved, err
+}
+
+//DequeueWait removes the next value the limits allow, like TryDequeue, waiting for a value and for the limits to allow it.
+//Returns the context error if the context ends first.
+//Panics on an uninitialized queue.
+func (rq *RateLimitedQueue) DequeueWait(ctx context.Context) (valueRemoved interface{}, err error) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	for {
+		rq.mutex.Lock()
+		valueRemoved, wait, err := rq.dequeueValue()
+		changed := rq.changed
+		rq.mutex.Unlock()
+
+		if err == nil && wait == 0 {
+			return valueRemoved, nil
+		}
+		if err != nil {
+			//Empty queue - values can also show up in the wrapped queue without a notification
+			wait = rq.options.PollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		case <-rq.options.Clock.After(wait):
+		}
+	}
+}
+
+//*************** Rate Limited Queue Internal Structure ***************
+
+//Number of dequeues between sweeps of idle key buckets
+const sweepEvery = 1024
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+//notify wakes up waiting consumers. No locking.
+func (rq *RateLimitedQueue) notify() {
+	close(rq.changed)
+	rq.changed = make(chan struct{})
+}
+
+//heldValue is a value set aside while its key is limited.
+type heldValue struct {
+	value interface{}
+	//Position in the order the values left the wrapped queue
+	seq uint64
+}
+
+//dequeueValue removes the next value the limits allow.
+//Returns how long to wait instead if they don't, an error if the queue is empty. No locking.
+func (rq *RateLimitedQueue) dequeueValue() (valueRemoved interface{}, wait time.Duration, err error) {
+	if rq.heldCount == 0 && rq.values.Length() == 0 {
+		return nil, 0, errors.New("Queue is empty")
+	}
+
+	now := rq.options.Clock.Now()
+	if wait = rq.global.wait(now); wait > 0 {
+		return nil, wait, nil
+	}
+	if rq.options.KeyFunc == nil {
+		valueRemoved, err = rq.values.Dequeue()
+		if err != nil {
+			return nil, 0, errors.New("Queue is empty")
+		}
+		rq.global.take()
+		return valueRemoved, 0, nil
+	}
+
+	//Held values are older than anything still in the wrapped queue
+	valueRemoved, wait, ok := rq.dequeueHeld(now)
+	if ok {
+		return valueRemoved, 0, nil
+	}
+	for {
+		front, err := rq.values.Dequeue()
+		if err != nil {
+			break
+		}
+		key := rq.options.KeyFunc(front)
+		keyBucket := rq.keyBucket(key, now)
+		keyWait := keyBucket.wait(now)
+		if keyWait == 0 && rq.held[key] == nil {
+			rq.global.take()
+			keyBucket.take()
+			rq.sweep(now)
+			return front, 0, nil
+		}
+		rq.hold(key, front)
+		if wait == 0 || keyWait < wait {
+			wait = keyWait
+		}
+	}
+	if wait == 0 {
+		return nil, 0, errors.New("Queue is empty")
+	}
+	return nil, wait, nil
+}
+
+//dequeueHeld removes the oldest held value of a key that may continue.
+//Returns the shortest wait of the held keys if there's none. No locking.
+func (rq *RateLimitedQueue) dequeueHeld(now time.Time) (valueRemoved interface{}, wait time.Duration, ok bool) {
+	var readyKey interface{}
+	var oldest *heldValue
+	for key, values := range rq.held {
+		if keyWait := rq.keyBucket(key, now).wait(now); keyWait > 0 {
+			if wait == 0 || keyWait < wait {
+				wait = keyWait
+			}
+			continue
+		}
+		front, _ := values.Peek()
+		if oldest == nil || front.(*heldValue).seq < oldest.seq {
+			readyKey, oldest = key, front.(*heldValue)
+		}
+	}
+	if oldest == nil {
+		return nil, wait, false
+	}
+
+	values := rq.held[readyKey]
+	values.Dequeue()
+	if values.Length() == 0 {
+		delete(rq.held, readyKey)
+	}
+	rq.heldCount--
+	rq.global.take()
+	rq.keyBucket(readyKey, now).take()
+	rq.sweep(now)
+	return oldest.value, 0, true
+}
+
+//hold sets value aside until its key may continue. No locking.
+func (rq *RateLimitedQueue) hold(key interface{}, value interface{}) {
+	values := rq.held[key]
+	if values == nil {
+		values = queue.NewQueue()
+		rq.held[key] = values
+	}
+	values.Enqueue(&heldValue{value: value, seq: rq.nextSeq})
+	rq.nextSeq++
+	rq.heldCount++
+}
+
+//keyBucket returns the bucket of a key, creating a full one if the key has none. No locking.
+func (rq *RateLimitedQueue) keyBucket(key interface{}, now time.Time) *bucket {
+	keyBucket := rq.keyed[key]
+	if keyBucket == nil {
+		keyBucket = newBucket(rq.keyLimit, now)
+		rq.keyed[key] = keyBucket
+	}
+	return keyBucket
+}
+
+//sweep forgets key buckets that are full again, they behave the same as new ones. No locking.
+func (rq *RateLimitedQueue) sweep(now time.Time) {
+	rq.sinceSweep++
+	if rq.sinceSweep < sweepEvery {
+		return
+	}
+	rq.sinceSweep = 0
+	for key, keyBucket := range rq.keyed {
+		if keyBucket.isFull(now) {
+			delete(rq.keyed, key)
+		}
+	}
+}
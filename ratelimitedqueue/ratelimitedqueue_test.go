@@ -0,0 +1,214 @@
+package ratelimitedqueue_test
+
+import (
+	"context"
+	"datatypes/queue"
+	. "datatypes/ratelimitedqueue"
+	"sync"
+	"testing"
+	"time"
+)
+
+//skippingClock jumps forward instead of sleeping, so waits finish right away and are recorded in the clock.
+type skippingClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func newSkippingClock() *skippingClock {
+	return &skippingClock{now: time.Unix(1000, 0)}
+}
+
+func (c *skippingClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *skippingClock) After(duration time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+	channel := make(chan time.Time, 1)
+	channel <- c.now
+	return channel
+}
+
+func (c *skippingClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+func enqueueAll(rq *RateLimitedQueue, values ...interface{}) {
+	for _, value := range values {
+		rq.Enqueue(value)
+	}
+}
+
+//*************** Public Interface Test ***************
+
+func TestTryDequeue(t *testing.T) {
+	clock := newSkippingClock()
+	rq := NewRateLimitedQueue(nil, Options{Limit: Limit{Rate: 2, Burst: 3}, Clock: clock})
+	if _, err := rq.TryDequeue(); err == nil {
+		t.Errorf("Expected an error on an empty queue")
+	}
+	enqueueAll(rq, 1, 2, 3, 4, 5)
+
+	for i := 1; i <= 3; i++ {
+		if value, err := rq.TryDequeue(); err != nil || value != i {
+			t.Errorf("Expected burst value %d, got %v with error %v", i, value, err)
+		}
+	}
+	if _, err := rq.TryDequeue(); err == nil {
+		t.Errorf("Expected the rate limit to be exceeded after the burst")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if value, err := rq.TryDequeue(); err != nil || value != 4 {
+		t.Errorf("Expected 4 after half a second, got %v with error %v", value, err)
+	}
+	if rq.Length() != 1 {
+		t.Errorf("Expected length 1, got %d", rq.Length())
+	}
+}
+
+func TestDequeueWaitPacing(t *testing.T) {
+	clock := newSkippingClock()
+	rq := NewRateLimitedQueue(queue.NewQueue(), Options{Limit: Limit{Rate: 10, Burst: 1}, Clock: clock})
+	enqueueAll(rq, 1, 2, 3, 4, 5)
+
+	start := clock.Now()
+	for i := 1; i <= 5; i++ {
+		if value, err := rq.DequeueWait(context.Background()); err != nil || value != i {
+			t.Fatalf("Expected %d, got %v with error %v", i, value, err)
+		}
+	}
+	if elapsed := clock.Now().Sub(start); elapsed != 400*time.Millisecond {
+		t.Errorf("Expected 5 values at 10 per second to take 400ms, took %v", elapsed)
+	}
+}
+
+func TestSetLimit(t *testing.T) {
+	clock := newSkippingClock()
+	rq := NewRateLimitedQueue(nil, Options{Limit: Limit{Rate: 1}, Clock: clock})
+	enqueueAll(rq, 1, 2, 3)
+	rq.TryDequeue()
+
+	rq.SetLimit(Limit{Rate: 100})
+	start := clock.Now()
+	rq.DequeueWait(context.Background())
+	if elapsed := clock.Now().Sub(start); elapsed != 10*time.Millisecond {
+		t.Errorf("Expected to wait 10ms after raising the rate, waited %v", elapsed)
+	}
+
+	rq.SetLimit(Limit{})
+	if _, err := rq.TryDequeue(); err != nil {
+		t.Errorf("Expected no limit after setting a zero rate, got %s", err.Error())
+	}
+}
+
+func TestKeyLimits(t *testing.T) {
+	clock := newSkippingClock()
+	rq := NewRateLimitedQueue(nil, Options{
+		KeyFunc:  func(value interface{}) interface{} { return value.(string)[:1] },
+		KeyLimit: Limit{Rate: 1, Burst: 2},
+		Clock:    clock,
+	})
+	enqueueAll(rq, "a1", "b1", "a2", "a3", "b2")
+
+	served := []interface{}{}
+	for i := 0; i < 3; i++ {
+		value, err := rq.TryDequeue()
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err.Error())
+		}
+		served = append(served, value)
+	}
+	//a3 is over the limit of key a, b2 goes first
+	if value, err := rq.TryDequeue(); err != nil || value != "b2" {
+		t.Errorf("Expected b2 right away, got %v with error %v", value, err)
+	}
+	if _, err := rq.TryDequeue(); err == nil {
+		t.Errorf("Expected key a to be limited")
+	}
+	if rq.Length() != 1 {
+		t.Errorf("Expected the held value to be counted, got length %d", rq.Length())
+	}
+
+	start := clock.Now()
+	value, _ := rq.DequeueWait(context.Background())
+	if value != "a3" || clock.Now().Sub(start) != time.Second {
+		t.Errorf("Expected a3 after a second, got %v after %v", value, clock.Now().Sub(start))
+	}
+
+	rq.SetKeyLimit(Limit{})
+	enqueueAll(rq, "a4", "a5")
+	rq.TryDequeue()
+	if _, err := rq.TryDequeue(); err != nil {
+		t.Errorf("Expected no key limit after setting a zero rate, got %s", err.Error())
+	}
+}
+
+func TestLimitedKeyDoesNotBlockOthers(t *testing.T) {
+	clock := newSkippingClock()
+	rq := NewRateLimitedQueue(nil, Options{
+		KeyFunc:  func(value interface{}) interface{} { return value.(string)[:1] },
+		KeyLimit: Limit{Rate: 1, Burst: 1},
+		Clock:    clock,
+	})
+	enqueueAll(rq, "a1", "a2", "a3", "b1", "c1", "a4", "b2", "c2")
+
+	//Every key gets one value right away, oldest first, then each second
+	cases := []struct {
+		expectedValue interface{}
+		expectedWait  time.Duration
+	}{
+		{"a1", 0},
+		{"b1", 0},
+		{"c1", 0},
+		{"a2", time.Second},
+		{"b2", 0},
+		{"c2", 0},
+		{"a3", time.Second},
+		{"a4", time.Second},
+	}
+	for i, aCase := range cases {
+		start := clock.Now()
+		value, err := rq.DequeueWait(context.Background())
+		if err != nil || value != aCase.expectedValue || clock.Now().Sub(start) != aCase.expectedWait {
+			t.Errorf("Error in case %d. Expected %v after %v, got %v after %v (error: %v)", i, aCase.expectedValue, aCase.expectedWait, value, clock.Now().Sub(start), err)
+		}
+	}
+	if rq.Length() != 0 {
+		t.Errorf("Expected an empty queue, got length %d", rq.Length())
+	}
+}
+
+func TestDequeueWaitCancel(t *testing.T) {
+	rq := NewRateLimitedQueue(nil, Options{Limit: Limit{Rate: 0.001}})
+	enqueueAll(rq, 1, 2)
+	rq.TryDequeue()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := rq.DequeueWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWrappedQueue(t *testing.T) {
+	values := queue.NewQueue()
+	rq := NewRateLimitedQueue(values, Options{PollInterval: time.Millisecond})
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		//Enqueued without the wrapper, picked up by polling
+		values.Enqueue("direct")
+	}()
+	if value, err := rq.DequeueWait(context.Background()); err != nil || value != "direct" {
+		t.Errorf("Expected direct, got %v with error %v", value, err)
+	}
+}
@@ -0,0 +1,272 @@
+//Reliablequeue is an implementation of a FIFO queue with acknowledged delivery.
+//Receive hides a value for a visibility timeout instead of removing it.
+//The value is deleted once acknowledged, and delivered again if it is rejected or the timeout passes.
+//Values delivered too many times are moved to a dead-letter queue.
+//Safe to use concurrently.
+package reliablequeue
+
+import (
+	"container/heap"
+	"crypto/rand"
+	"datatypes/queue"
+	"errors"
+	"sync"
+	"time"
+)
+
+//*************** Reliable Queue Public Interface ***************
+
+//Default visibility timeout used when Options.VisibilityTimeout is zero.
+const DefaultVisibilityTimeout = 30 * time.Second
+
+//Options configures a ReliableQueue. The zero value is valid.
+type Options struct {
+	//How long a received value stays hidden before it is delivered again.
+	VisibilityTimeout time.Duration
+	//Number of deliveries after which an unacknowledged value goes to the dead-letter queue. Zero means no limit.
+	MaxDeliveries int
+	//Receives values that exceeded MaxDeliveries. A new queue is created when nil.
+	DeadLetterQueue *queue.Queue
+	//Source of the current time. time.Now is used when nil.
+	Clock func() time.Time
+}
+
+//Message is a value handed out by Receive.
+type Message struct {
+	Value interface{}
+	//Opaque random token identifying this delivery in calls to Ack and Nack. Becomes stale once the value is delivered again.
+	ReceiptHandle string
+	//Number of times the value was delivered, including this one.
+	DeliveryCount int
+}
+
+//ReliableQueue is a FIFO queue with acknowledged delivery. Goroutine safe.
+type ReliableQueue struct {
+	options  Options
+	ready    *queue.Queue
+	inFlight map[string]*delivery
+	//In-flight deliveries by deadline, so expired ones are found without scanning
+	deadlines deliveryHeap
+	nextId    uint64
+	mutex     sync.Mutex
+}
+
+//NewReliableQueue initializes an empty ReliableQueue. Recommended way of initialization.
+func NewReliableQueue(options Options) *ReliableQueue {
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = DefaultVisibilityTimeout
+	}
+	if options.DeadLetterQueue == nil {
+		options.DeadLetterQueue = queue.NewQueue()
+	}
+	if options.Clock == nil {
+		options.Clock = time.Now
+	}
+	return &ReliableQueue{options: options, ready: queue.NewQueue(), inFlight: map[string]*delivery{}}
+}
+
+//Length returns the number of values waiting to be received, expired deliveries included.
+//Returns 0 on an uninitialized ReliableQueue.
+func (rq *ReliableQueue) Length() int {
+	if rq == nil {
+		return 0
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.releaseExpired()
+	return rq.ready.Length()
+}
+
+//InFlight returns the number of values received, but not yet acknowledged or expired.
+//Returns 0 on an uninitialized ReliableQueue.
+func (rq *ReliableQueue) InFlight() int {
+	if rq == nil {
+		return 0
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.releaseExpired()
+	return len(rq.inFlight)
+}
+
+//DeadLetterQueue returns the queue holding values that exceeded the maximum number of deliveries.
+//Returns nil on an uninitialized ReliableQueue.
+func (rq *ReliableQueue) DeadLetterQueue() *queue.Queue {
+	if rq == nil {
+		return nil
+	}
+	return rq.options.DeadLetterQueue
+}
+
+//Enqueue adds value to back of the queue.
+//Panics on an uninitialized queue.
+func (rq *ReliableQueue) Enqueue(value interface{}) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.nextId++
+	rq.ready.Enqueue(&message{id: rq.nextId, value: value})
+}
+
+//Receive hands out the value at the front of the queue and hides it for the visibility timeout.
+//If no value is available, returns an error.
+//Panics on an uninitialized queue.
+func (rq *ReliableQueue) Receive() (Message, error) {
+	if rq == nil {
+		panic("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.releaseExpired()
+
+	value, err := rq.ready.Dequeue()
+	if err != nil {
+		return Message{}, errors.New("Queue is empty")
+	}
+	msg, ok := value.(*message)
+	if !ok {
+		if panic_on_internal_inconsistencies {
+			panic("Ready queue holds a value that is not a message")
+		}
+		return Message{}, errors.New("Ready queue holds a value that is not a message")
+	}
+
+	msg.deliveries++
+	//Random so one consumer can't guess and acknowledge another consumer's delivery
+	handle := rand.Text()
+	newDelivery := &delivery{handle: handle, message: msg, deadline: rq.options.Clock().Add(rq.options.VisibilityTimeout)}
+	rq.inFlight[handle] = newDelivery
+	heap.Push(&rq.deadlines, newDelivery)
+
+	return Message{Value: msg.value, ReceiptHandle: handle, DeliveryCount: msg.deliveries}, nil
+}
+
+//Ack deletes a received value. Returns an error if the handle is unknown or stale,
+//which happens when the visibility timeout passed and the value was made available again.
+func (rq *ReliableQueue) Ack(receiptHandle string) error {
+	if rq == nil {
+		return errors.New("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.releaseExpired()
+	inFlightDelivery, ok := rq.inFlight[receiptHandle]
+	if !ok {
+		return errors.New("Can't acknowledge - receipt handle is unknown or expired")
+	}
+	rq.removeInFlight(inFlightDelivery)
+	return nil
+}
+
+//Nack makes a received value available again right away, at the back of the queue.
+//Returns an error if the handle is unknown or stale.
+func (rq *ReliableQueue) Nack(receiptHandle string) error {
+	if rq == nil {
+		return errors.New("Queue is nil")
+	}
+
+	rq.mutex.Lock()
+	defer rq.mutex.Unlock()
+
+	rq.releaseExpired()
+	inFlightDelivery, ok := rq.inFlight[receiptHandle]
+	if !ok {
+		return errors.New("Can't reject - receipt handle is unknown or expired")
+	}
+	rq.removeInFlight(inFlightDelivery)
+	rq.makeAvailable(inFlightDelivery.message)
+	return nil
+}
+
+//*************** Reliable Queue Internal Structure ***************
+
+//Make runtime asserts fatal
+const (
+	panic_on_internal_inconsistencies = true
+)
+
+type message struct {
+	id         uint64
+	value      interface{}
+	deliveries int
+}
+
+type delivery struct {
+	handle   string
+	message  *message
+	deadline time.Time
+	//Position in the deadline heap
+	index int
+}
+
+//releaseExpired makes values whose visibility timeout passed available again, oldest deadline first.
+//Only looks at the expired deliveries. No locking.
+func (rq *ReliableQueue) releaseExpired() {
+	now := rq.options.Clock()
+	for len(rq.deadlines) > 0 && !now.Before(rq.deadlines[0].deadline) {
+		expired := heap.Pop(&rq.deadlines).(*delivery)
+		delete(rq.inFlight, expired.handle)
+		rq.makeAvailable(expired.message)
+	}
+}
+
+//removeInFlight forgets an acknowledged or rejected delivery. No locking.
+func (rq *ReliableQueue) removeInFlight(inFlightDelivery *delivery) {
+	delete(rq.inFlight, inFlightDelivery.handle)
+	heap.Remove(&rq.deadlines, inFlightDelivery.index)
+}
+
+//makeAvailable puts a message back into the ready queue, or into the dead-letter queue if it ran out of deliveries. No locking.
+func (rq *ReliableQueue) makeAvailable(msg *message) {
+	if rq.options.MaxDeliveries > 0 && msg.deliveries >= rq.options.MaxDeliveries {
+		rq.options.DeadLetterQueue.Enqueue(msg.value)
+		return
+	}
+	rq.ready.Enqueue(msg)
+}
+
+//deliveryHeap implements heap.Interface, ordered by deadline and message order.
+type deliveryHeap []*delivery
+
+func (h deliveryHeap) Len() int {
+	return len(h)
+}
+
+func (h deliveryHeap) Less(i, j int) bool {
+	if !h[i].deadline.Equal(h[j].deadline) {
+		return h[i].deadline.Before(h[j].deadline)
+	}
+	return h[i].message.id < h[j].message.id
+}
+
+func (h deliveryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *deliveryHeap) Push(x interface{}) {
+	pushed := x.(*delivery)
+	pushed.index = len(*h)
+	*h = append(*h, pushed)
+}
+
+func (h *deliveryHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,303 @@
+package reliablequeue_test
+
+import (
+	"datatypes/linearizability"
+	. "datatypes/reliablequeue"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+//fakeClock is advanced manually by the tests.
+type fakeClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+func newTestQueue(maxDeliveries int) (*ReliableQueue, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	rq := NewReliableQueue(Options{VisibilityTimeout: time.Minute, MaxDeliveries: maxDeliveries, Clock: clock.Now})
+	return rq, clock
+}
+
+//*************** Public Interface Test ***************
+
+func TestReceiveAndAck(t *testing.T) {
+	rq, _ := newTestQueue(0)
+	rq.Enqueue("first")
+	rq.Enqueue("second")
+
+	msg, err := rq.Receive()
+	if err != nil || msg.Value != "first" || msg.DeliveryCount != 1 {
+		t.Fatalf("Unexpected message %v (error: %v)", msg, err)
+	}
+	if rq.Length() != 1 || rq.InFlight() != 1 {
+		t.Errorf("Expected 1 ready and 1 in flight, got %d and %d", rq.Length(), rq.InFlight())
+	}
+
+	if err := rq.Ack(msg.ReceiptHandle); err != nil {
+		t.Errorf("Expected no error, got %s", err.Error())
+	}
+	if err := rq.Ack(msg.ReceiptHandle); err == nil {
+		t.Errorf("Expected an error acknowledging twice, got no error")
+	}
+	if rq.InFlight() != 0 {
+		t.Errorf("Expected no values in flight, got %d", rq.InFlight())
+	}
+
+	msg, _ = rq.Receive()
+	rq.Ack(msg.ReceiptHandle)
+	if _, err := rq.Receive(); err == nil {
+		t.Errorf("Expected an error receiving from an empty queue, got no error")
+	}
+}
+
+func TestVisibilityTimeout(t *testing.T) {
+	rq, clock := newTestQueue(0)
+	rq.Enqueue("job")
+
+	first, _ := rq.Receive()
+	if _, err := rq.Receive(); err == nil {
+		t.Errorf("Value should be hidden during the visibility timeout")
+	}
+
+	clock.Advance(time.Minute)
+	second, err := rq.Receive()
+	if err != nil || second.Value != "job" || second.DeliveryCount != 2 {
+		t.Fatalf("Expected the value to be delivered again, got %v (error: %v)", second, err)
+	}
+
+	//The first delivery is stale now
+	if err := rq.Ack(first.ReceiptHandle); err == nil {
+		t.Errorf("Expected an error acknowledging a stale delivery, got no error")
+	}
+	if err := rq.Ack(second.ReceiptHandle); err != nil {
+		t.Errorf("Expected no error, got %s", err.Error())
+	}
+}
+
+func TestNack(t *testing.T) {
+	rq, _ := newTestQueue(0)
+	rq.Enqueue("a")
+	rq.Enqueue("b")
+
+	msg, _ := rq.Receive()
+	if err := rq.Nack(msg.ReceiptHandle); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if err := rq.Nack(msg.ReceiptHandle); err == nil {
+		t.Errorf("Expected an error rejecting twice, got no error")
+	}
+
+	//Rejected values go to the back of the queue
+	cases := []struct {
+		expectedValue         interface{}
+		expectedDeliveryCount int
+	}{
+		{"b", 1},
+		{"a", 2},
+	}
+	for i, aCase := range cases {
+		msg, err := rq.Receive()
+		if err != nil || msg.Value != aCase.expectedValue || msg.DeliveryCount != aCase.expectedDeliveryCount {
+			t.Errorf("Error in case %d. Expected %v delivered %d times, got %v (error: %v)", i, aCase.expectedValue, aCase.expectedDeliveryCount, msg, err)
+		}
+	}
+}
+
+func TestReceiptHandle(t *testing.T) {
+	rq, clock := newTestQueue(0)
+	rq.Enqueue("a")
+
+	//Handles of earlier or other deliveries don't reveal the next one
+	first, _ := rq.Receive()
+	clock.Advance(time.Minute)
+	second, _ := rq.Receive()
+	if first.ReceiptHandle == second.ReceiptHandle {
+		t.Errorf("Expected a new handle for each delivery, got %s twice", first.ReceiptHandle)
+	}
+	if rq.Ack("1-2") == nil {
+		t.Errorf("Expected an error acknowledging a guessed handle, got no error")
+	}
+	if len(second.ReceiptHandle) < 16 {
+		t.Errorf("Expected a long random handle, got %s", second.ReceiptHandle)
+	}
+	if err := rq.Ack(second.ReceiptHandle); err != nil {
+		t.Errorf("Expected no error, got %s", err.Error())
+	}
+}
+
+func TestExpiryOrder(t *testing.T) {
+	rq, clock := newTestQueue(0)
+	for _, value := range []string{"a", "b", "c", "d"} {
+		rq.Enqueue(value)
+	}
+
+	//a and b expire first, c is acknowledged, d expires later
+	a, _ := rq.Receive()
+	rq.Receive()
+	clock.Advance(30 * time.Second)
+	c, _ := rq.Receive()
+	rq.Receive()
+	rq.Ack(c.ReceiptHandle)
+	if err := rq.Ack(a.ReceiptHandle); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	clock.Advance(30 * time.Second)
+	if rq.Length() != 1 || rq.InFlight() != 1 {
+		t.Errorf("Expected 1 ready and 1 in flight, got %d and %d", rq.Length(), rq.InFlight())
+	}
+	clock.Advance(30 * time.Second)
+
+	cases := []struct {
+		expectedValue interface{}
+	}{
+		{"b"},
+		{"d"},
+	}
+	for i, aCase := range cases {
+		msg, err := rq.Receive()
+		if err != nil || msg.Value != aCase.expectedValue || msg.DeliveryCount != 2 {
+			t.Errorf("Error in case %d. Expected %v delivered twice, got %v (error: %v)", i, aCase.expectedValue, msg, err)
+		}
+	}
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	rq, clock := newTestQueue(2)
+	rq.Enqueue("poison")
+
+	msg, _ := rq.Receive()
+	rq.Nack(msg.ReceiptHandle)
+	rq.Receive()
+	clock.Advance(time.Minute)
+
+	if rq.Length() != 0 || rq.InFlight() != 0 {
+		t.Errorf("Value should have left the queue, %d ready, %d in flight", rq.Length(), rq.InFlight())
+	}
+	if value, err := rq.DeadLetterQueue().Dequeue(); err != nil || value != "poison" {
+		t.Errorf("Expected value in the dead-letter queue, got %v (error: %v)", value, err)
+	}
+}
+
+func TestNilQueue(t *testing.T) {
+	var nilQueue *ReliableQueue
+	if nilQueue.Length() != 0 || nilQueue.InFlight() != 0 || nilQueue.DeadLetterQueue() != nil {
+		t.Errorf("Nil queue should be empty")
+	}
+	if nilQueue.Ack("1-1") == nil || nilQueue.Nack("1-1") == nil {
+		t.Errorf("Ack and Nack on a nil queue should return an error")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Receive on a nil queue should cause a panic, did not")
+		}
+	}()
+	nilQueue.Receive()
+}
+
+func Example() {
+	jobs := NewReliableQueue(Options{VisibilityTimeout: time.Minute, MaxDeliveries: 3})
+	jobs.Enqueue("send email")
+
+	msg, err := jobs.Receive()
+	if err != nil {
+		//Handle error...
+	}
+	//Process the job, then delete it
+	jobs.Ack(msg.ReceiptHandle)
+
+	fmt.Printf("Processed: %v, remaining: %d", msg.Value, jobs.Length()+jobs.InFlight())
+	//Output: Processed: send email, remaining: 0
+}
+
+//*************** Concurrency Test ***************
+
+//TestConcurrency receives and acknowledges from multiple goroutines. Run with `go test -race` for better race detection.
+func TestConcurrency(t *testing.T) {
+	rq, _ := newTestQueue(0)
+	for i := 0; i < 1000; i++ {
+		rq.Enqueue(i)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				msg, err := rq.Receive()
+				if err != nil {
+					return
+				}
+				if msg.Value.(int)%2 == 0 {
+					rq.Ack(msg.ReceiptHandle)
+				} else {
+					rq.Nack(msg.ReceiptHandle)
+					msg, _ := rq.Receive()
+					rq.Ack(msg.ReceiptHandle)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rq.Length() != 0 || rq.InFlight() != 0 {
+		t.Errorf("Expected an empty queue, %d ready, %d in flight", rq.Length(), rq.InFlight())
+	}
+}
+
+//TestLinearizability records concurrent operations on a ReliableQueue and checks them against a sequential FIFO model.
+//A received and acknowledged value counts as dequeued, Receive is the point where it leaves the queue.
+func TestLinearizability(t *testing.T) {
+	rq, _ := newTestQueue(0)
+	recorder := linearizability.NewRecorder()
+	var wg sync.WaitGroup
+
+	for client := 0; client < 4; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				value := fmt.Sprintf("%d-%d", client, i)
+				recorder.Record(client, linearizability.Input{Op: linearizability.Enqueue, Value: value}, func() interface{} {
+					rq.Enqueue(value)
+					return linearizability.Output{}
+				})
+				recorder.Record(client, linearizability.Input{Op: linearizability.Dequeue}, func() interface{} {
+					msg, err := rq.Receive()
+					if err != nil {
+						return linearizability.Output{Err: true}
+					}
+					if err := rq.Ack(msg.ReceiptHandle); err != nil {
+						t.Errorf("Expected no error, got %s", err.Error())
+					}
+					return linearizability.Output{Value: msg.Value}
+				})
+				recorder.Record(client, linearizability.Input{Op: linearizability.Length}, func() interface{} {
+					return linearizability.Output{Value: rq.Length()}
+				})
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	if !linearizability.CheckOperations(linearizability.QueueModel, recorder.History()) {
+		t.Errorf("Concurrent ReliableQueue history is not linearizable")
+	}
+}
@@ -0,0 +1,62 @@
+package stack
+
+import (
+	"datatypes/binarycodec"
+	"errors"
+)
+
+//*************** Binary Encoding ***************
+
+//MarshalBinary encodes the stack in the binarycodec format, elements are encoded with gob.
+//Values are collected under a read lock, so the encoding is a consistent view.
+func (s *Stack) MarshalBinary() ([]byte, error) {
+	return s.MarshalBinaryWith(binarycodec.GobCodec{})
+}
+
+//UnmarshalBinary replaces the content of the stack with values encoded by MarshalBinary.
+func (s *Stack) UnmarshalBinary(data []byte) error {
+	return s.UnmarshalBinaryWith(data, binarycodec.GobCodec{})
+}
+
+//GobEncode implements gob.GobEncoder, so the stack can be a part of gob encoded structures.
+func (s *Stack) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+//GobDecode implements gob.GobDecoder.
+func (s *Stack) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+//MarshalBinaryWith encodes the stack in the binarycodec format, elements are encoded with the codec.
+//Returns an error on an uninitialized Stack.
+func (s *Stack) MarshalBinaryWith(codec binarycodec.Codec) ([]byte, error) {
+	if s == nil {
+		return nil, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	values := s.values()
+	s.rwMutex.RUnlock()
+
+	return binarycodec.Encode(binarycodec.KindStack, values, codec)
+}
+
+//UnmarshalBinaryWith replaces the content of the stack with values decoded by the codec.
+//The stack is left unchanged if decoding fails. Applies the capacity like Restore.
+//Returns an error on an uninitialized Stack.
+func (s *Stack) UnmarshalBinaryWith(data []byte, codec binarycodec.Codec) error {
+	if s == nil {
+		return errors.New("Stack is nil")
+	}
+
+	values, err := binarycodec.Decode(binarycodec.KindStack, data, codec)
+	if err != nil {
+		return err
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	return s.replaceValues(values)
+}
@@ -0,0 +1,101 @@
+package stack_test
+
+import (
+	"bytes"
+	. "datatypes/stack"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+//intCodec stores small non-negative ints as a single byte.
+type intCodec struct{}
+
+func (intCodec) EncodeElement(value interface{}) ([]byte, error) {
+	number, ok := value.(int)
+	if !ok || number < 0 || number > 255 {
+		return nil, errors.New("Value doesn't fit into a byte")
+	}
+	return []byte{byte(number)}, nil
+}
+
+func (intCodec) DecodeElement(data []byte) (interface{}, error) {
+	if len(data) != 1 {
+		return nil, errors.New("Expected a single byte")
+	}
+	return int(data[0]), nil
+}
+
+//*************** Binary Encoding Test ***************
+
+func TestBinaryRoundTrip(t *testing.T) {
+	setVariablesToDefaults()
+	data, err := tenElementStack.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	decodedStack := NewStack()
+	if err := decodedStack.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if decodedStack.Length() != 10 {
+		t.Fatalf("Expected length 10, got %d", decodedStack.Length())
+	}
+	for _, expectedValue := range []string{"9", "8", "7"} {
+		value, _ := decodedStack.Pop()
+		if value != expectedValue {
+			t.Errorf("Expected value %v, got %v", expectedValue, value)
+		}
+	}
+
+	if _, err := nilStack.MarshalBinary(); err == nil {
+		t.Errorf("Expected an error encoding a nil stack, got no error")
+	}
+}
+
+func TestBinaryWithCodec(t *testing.T) {
+	aStack := NewStack()
+	aStack.Push(1)
+	aStack.Push(2)
+
+	data, err := aStack.MarshalBinaryWith(intCodec{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	//Header, version, count and two one byte elements with their length prefixes
+	if len(data) != 9 {
+		t.Errorf("Expected 9 bytes, got %d", len(data))
+	}
+
+	decodedStack := NewStack()
+	if err := decodedStack.UnmarshalBinaryWith(data, intCodec{}); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := decodedStack.Pop(); value != 2 {
+		t.Errorf("Expected value 2, got %v", value)
+	}
+
+	aStack.Push("not an int")
+	if _, err := aStack.MarshalBinaryWith(intCodec{}); err == nil {
+		t.Errorf("Expected a codec error, got no error")
+	}
+}
+
+func TestGob(t *testing.T) {
+	original := NewStack()
+	original.Push("bottom")
+	original.Push("top")
+
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(original); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	decoded := NewStack()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := decoded.Pop(); value != "top" {
+		t.Errorf("Expected value top, got %v", value)
+	}
+}
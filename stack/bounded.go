@@ -0,0 +1,204 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//*************** Maximum Depth ***************
+
+//OverflowMode decides what happens when a value is pushed to a full stack.
+type OverflowMode int
+
+const (
+	//TryPush and PushContext return an *OverflowError, Push panics with it. The value is not added.
+	OverflowReject OverflowMode = iota
+	//Push waits until a value is popped. PushContext gives up when its context ends, TryPush doesn't wait.
+	OverflowBlock
+	//The bottom value is dropped to make room, the stack keeps the newest values.
+	OverflowDiscardBottom
+)
+
+//OverflowError is returned by TryPush and PushContext when a full stack can't take the value.
+type OverflowError struct {
+	Capacity int
+	//The value that was not pushed
+	Value interface{}
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("Stack is full - capacity is %d", e.Capacity)
+}
+
+//SetCapacity limits the number of values in the stack. Zero, the default, means no limit.
+//In OverflowDiscardBottom mode values over the new capacity are dropped from the bottom right away,
+//in the other modes they are kept and the stack stays full until enough values are popped.
+//Expired values take space until they are dropped.
+//Panics on an uninitialized stack.
+func (s *Stack) SetCapacity(capacity int, mode OverflowMode) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	if capacity < 0 {
+		capacity = 0
+	}
+	s.capacity = capacity
+	s.overflowMode = mode
+	if mode == OverflowDiscardBottom {
+		for capacity > 0 && s.length > capacity {
+			s.discardBottom()
+		}
+	}
+	//Waiting pushes have to check the new capacity
+	s.signalSpaceFreed()
+}
+
+//Capacity returns the maximum number of values, 0 if the stack is unbounded. Returns 0 on an uninitialized Stack.
+func (s *Stack) Capacity() int {
+	if s == nil {
+		return 0
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.capacity
+}
+
+//IsFull reports whether a Push would overflow. Always false for an unbounded or uninitialized Stack.
+func (s *Stack) IsFull() bool {
+	if s == nil {
+		return false
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.isFullValue()
+}
+
+//TryPush is Push without waiting or panicking on a full stack.
+//Returns an *OverflowError if the stack is full in OverflowReject or OverflowBlock mode, an error if the stack is closed.
+//Unbounded open stacks never return an error.
+//Panics on an uninitialized stack.
+func (s *Stack) TryPush(value interface{}) error {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	return s.pushBounded(context.Background(), value, 0, true, false)
+}
+
+//PushContext is Push with a context ending the wait for space in OverflowBlock mode.
+//Returns the context error if the context ends first, an *OverflowError in OverflowReject mode
+//and an error if the stack is closed.
+//Panics on an uninitialized stack.
+func (s *Stack) PushContext(ctx context.Context, value interface{}) error {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	return s.pushBounded(ctx, value, 0, true, true)
+}
+
+//*************** Maximum Depth Internal Structure ***************
+
+//pushBounded pushes value, applying the overflow mode if the stack is full.
+//The default TTL is used if useDefaultTTL is set, ttl otherwise. Waits for space in OverflowBlock mode only if wait is set.
+func (s *Stack) pushBounded(ctx context.Context, value interface{}, ttl time.Duration, useDefaultTTL bool, wait bool) error {
+	for {
+		s.rwMutex.Lock()
+		if s.closed {
+			s.rwMutex.Unlock()
+			return errors.New("Stack is closed")
+		}
+		if useDefaultTTL {
+			ttl = s.defaultTTL
+		}
+
+		notifyExpired := noExpiredValues
+		if s.isFullValue() {
+			//Expired values at the top give their space back first
+			notifyExpired = s.dropExpiredTop()
+		}
+		if !s.isFullValue() || s.overflowMode == OverflowDiscardBottom {
+			for s.isFullValue() {
+				s.discardBottom()
+			}
+			s.pushValue(value, ttl)
+			s.rwMutex.Unlock()
+			notifyExpired()
+			return nil
+		}
+
+		if s.overflowMode == OverflowReject || !wait {
+			capacity := s.capacity
+			s.rwMutex.Unlock()
+			notifyExpired()
+			return &OverflowError{Capacity: capacity, Value: value}
+		}
+
+		if s.spaceFreed == nil {
+			s.spaceFreed = make(chan struct{})
+		}
+		spaceFreed := s.spaceFreed
+		s.rwMutex.Unlock()
+		notifyExpired()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-spaceFreed:
+		}
+	}
+}
+
+//isFullValue reports whether the stack is at capacity. No locking.
+func (s *Stack) isFullValue() bool {
+	return s.capacity > 0 && s.length >= s.capacity
+}
+
+//discardBottom removes the bottom value. Marks are moved down with the stack. No locking.
+func (s *Stack) discardBottom() {
+	bottom := s.bottomElement
+	if bottom == nil {
+		if panic_on_internal_inconsistencies {
+			panic("Stack is full, but bottom element is nil")
+		}
+		return
+	}
+
+	s.bottomElement = bottom.nextElement
+	if s.bottomElement == nil {
+		s.topElement = nil
+	} else {
+		s.bottomElement.previousElement = nil
+	}
+	bottom.nextElement = nil
+	s.changeLength(-1)
+	s.clearExpiry(bottom)
+
+	//A mark on the discarded value now stands for an empty stack below the values pushed after it
+	for i := range s.marks {
+		if s.marks[i].topElement == bottom {
+			s.marks[i].topElement = nil
+		}
+		if s.marks[i].length > 0 {
+			s.marks[i].length--
+		}
+	}
+}
+
+//signalSpaceFreed wakes up pushes waiting for space. No locking.
+func (s *Stack) signalSpaceFreed() {
+	if s.spaceFreed != nil {
+		close(s.spaceFreed)
+		s.spaceFreed = nil
+	}
+}
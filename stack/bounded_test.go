@@ -0,0 +1,180 @@
+package stack_test
+
+import (
+	"context"
+	. "datatypes/stack"
+	"errors"
+	"testing"
+	"time"
+)
+
+//*************** Maximum Depth Test ***************
+
+func TestOverflowReject(t *testing.T) {
+	aStack := NewStack()
+	if aStack.IsFull() || aStack.Capacity() != 0 {
+		t.Errorf("Expected a new stack to be unbounded")
+	}
+	aStack.SetCapacity(2, OverflowReject)
+	aStack.Push(1)
+	if err := aStack.TryPush(2); err != nil || !aStack.IsFull() {
+		t.Fatalf("Expected the stack to be full without an error, got %v", err)
+	}
+
+	err := aStack.TryPush(3)
+	var overflow *OverflowError
+	if !errors.As(err, &overflow) || overflow.Capacity != 2 || overflow.Value != 3 {
+		t.Fatalf("Expected an *OverflowError for value 3, got %v", err)
+	}
+	if err := aStack.PushContext(context.Background(), 3); !errors.As(err, &overflow) {
+		t.Errorf("Expected PushContext to overflow too, got %v", err)
+	}
+	for i, push := range []func(){
+		func() { aStack.Push(3) },
+		func() { aStack.PushWithTTL(3, time.Minute) },
+	} {
+		func() {
+			defer func() {
+				if recovered, ok := recover().(*OverflowError); !ok || recovered.Value != 3 {
+					t.Errorf("Error in case %d. Expected a panic with an *OverflowError, got %v", i, recovered)
+				}
+			}()
+			push()
+		}()
+	}
+	if top, _ := aStack.Peek(); top != 2 || aStack.Length() != 2 {
+		t.Errorf("Expected the rejected values not to be added")
+	}
+
+	aStack.Pop()
+	if err := aStack.TryPush(3); err != nil {
+		t.Errorf("Expected space after a pop, got %s", err.Error())
+	}
+}
+
+func TestOverflowBlock(t *testing.T) {
+	aStack := NewStack()
+	aStack.SetCapacity(1, OverflowBlock)
+	aStack.Push("first")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := aStack.PushContext(ctx, "late"); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	var overflow *OverflowError
+	if err := aStack.TryPush("late"); !errors.As(err, &overflow) {
+		t.Errorf("Expected TryPush not to wait, got %v", err)
+	}
+
+	pushed := make(chan struct{})
+	go func() {
+		aStack.Push("second")
+		pushed <- struct{}{}
+	}()
+	select {
+	case <-pushed:
+		t.Fatalf("Expected Push to block on a full stack")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if value, _ := aStack.Pop(); value != "first" {
+		t.Errorf("Expected first, got %v", value)
+	}
+	<-pushed
+	if top, _ := aStack.Peek(); top != "second" {
+		t.Errorf("Expected second on top, got %v", top)
+	}
+
+	//Raising the capacity also releases waiting pushes
+	go func() {
+		aStack.Push("third")
+		pushed <- struct{}{}
+	}()
+	time.Sleep(5 * time.Millisecond)
+	aStack.SetCapacity(2, OverflowBlock)
+	<-pushed
+	if aStack.Length() != 2 {
+		t.Errorf("Expected the push to succeed after raising the capacity, got length %d", aStack.Length())
+	}
+}
+
+func TestOverflowDiscardBottom(t *testing.T) {
+	aStack := NewStack()
+	for i := 0; i < 5; i++ {
+		aStack.Push(i)
+	}
+	aStack.SetCapacity(3, OverflowDiscardBottom)
+	if bottom, _ := aStack.Bottom(); bottom != 2 || aStack.Length() != 3 {
+		t.Fatalf("Expected SetCapacity to keep 2 to 4, got bottom %v and length %d", bottom, aStack.Length())
+	}
+
+	mark := aStack.Mark()
+	aStack.Push(5)
+	aStack.Push(6)
+	if bottom, _ := aStack.Bottom(); bottom != 4 || !aStack.IsFull() {
+		t.Errorf("Expected the oldest values to slide out, got bottom %v", bottom)
+	}
+
+	//The marked top went out at the bottom, rolling back leaves nothing behind
+	if popped, err := aStack.RollbackTo(mark); err != nil || popped != 2 || aStack.Length() != 1 {
+		t.Errorf("Expected the values pushed after the mark popped, got %d with error %v and length %d", popped, err, aStack.Length())
+	}
+}
+
+func TestExpiredValuesFreeSpace(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aStack := NewStack()
+	aStack.SetClock(clock.Now)
+	aStack.SetCapacity(1, OverflowReject)
+	aStack.PushWithTTL("short", time.Second)
+	clock.Advance(2 * time.Second)
+
+	if err := aStack.TryPush("next"); err != nil || aStack.ExpiredCount() != 1 {
+		t.Errorf("Expected the expired top to make room, got %v", err)
+	}
+}
+
+func TestReplaceOverCapacity(t *testing.T) {
+	source := NewStack()
+	for i := 1; i <= 4; i++ {
+		source.Push(i)
+	}
+	data, _ := source.MarshalJSON()
+	binaryData, _ := source.MarshalBinary()
+
+	cases := []struct {
+		mode           OverflowMode
+		replace        func(aStack *Stack) error
+		expectedValues []interface{}
+		expectedError  bool
+	}{
+		{OverflowReject, func(aStack *Stack) error { return aStack.Restore(source.Snapshot()) }, []interface{}{"old"}, true},
+		{OverflowBlock, func(aStack *Stack) error { return aStack.UnmarshalJSON(data) }, []interface{}{"old"}, true},
+		{OverflowReject, func(aStack *Stack) error { return aStack.UnmarshalBinary(binaryData) }, []interface{}{"old"}, true},
+		{OverflowDiscardBottom, func(aStack *Stack) error { return aStack.Restore(source.Snapshot()) }, []interface{}{3, 4}, false},
+		{OverflowDiscardBottom, func(aStack *Stack) error { return aStack.UnmarshalJSON(data) }, []interface{}{3.0, 4.0}, false},
+	}
+	for i, aCase := range cases {
+		aStack := NewStack()
+		aStack.Push("old")
+		aStack.SetCapacity(2, aCase.mode)
+
+		err := aCase.replace(aStack)
+		var overflow *OverflowError
+		if aCase.expectedError != errors.As(err, &overflow) {
+			t.Errorf("Error in case %d. Expected an *OverflowError: %v, got %v", i, aCase.expectedError, err)
+		}
+		values := aStack.Snapshot().Values()
+		if len(values) != len(aCase.expectedValues) {
+			t.Errorf("Error in case %d. Expected %v, got %v", i, aCase.expectedValues, values)
+			continue
+		}
+		for j, value := range values {
+			if value != aCase.expectedValues[j] {
+				t.Errorf("Error in case %d. Expected %v, got %v", i, aCase.expectedValues, values)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package stack
+
+import (
+	"testing"
+)
+
+//*************** Fuzz Test ***************
+
+const (
+	fuzzPush = iota
+	fuzzPop
+	fuzzPeek
+	fuzzLength
+	fuzzPushSliding
+	numberOfFuzzOperations
+)
+
+const fuzzSlidingCapacity = 8
+
+//FuzzStack decodes the input into a sequence of operations, one byte per operation.
+//Every step is compared against a slice oracle and followed by an invariant check.
+func FuzzStack(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{fuzzPush, fuzzPeek, fuzzPop, fuzzPop})
+	f.Add([]byte{fuzzPush, fuzzPush, fuzzPop, fuzzPush, fuzzPop, fuzzPop, fuzzPeek})
+	f.Add([]byte{fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPush, fuzzPushSliding, fuzzPop, fuzzPushSliding})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := NewStack()
+		oracle := []interface{}{}
+
+		for step, operationByte := range data {
+			switch int(operationByte) % numberOfFuzzOperations {
+			case fuzzPush:
+				s.Push(step)
+				oracle = append(oracle, step)
+			case fuzzPop:
+				value, err := s.Pop()
+				if len(oracle) == 0 {
+					if err == nil {
+						t.Fatalf("Step %d. Pop on an empty stack returned no error", step)
+					}
+					break
+				}
+				top := oracle[len(oracle)-1]
+				if err != nil || value != top {
+					t.Fatalf("Step %d. Pop returned %v (error: %v), expected %v", step, value, err, top)
+				}
+				oracle = oracle[:len(oracle)-1]
+			case fuzzPeek:
+				value, err := s.Peek()
+				if len(oracle) == 0 {
+					if err == nil {
+						t.Fatalf("Step %d. Peek on an empty stack returned no error", step)
+					}
+					break
+				}
+				top := oracle[len(oracle)-1]
+				if err != nil || value != top {
+					t.Fatalf("Step %d. Peek returned %v (error: %v), expected %v", step, value, err, top)
+				}
+			case fuzzLength:
+			case fuzzPushSliding:
+				//Push onto a stack briefly bounded to the last fuzzSlidingCapacity values
+				s.SetCapacity(fuzzSlidingCapacity, OverflowDiscardBottom)
+				s.Push(step)
+				s.SetCapacity(0, OverflowReject)
+				oracle = append(oracle, step)
+				if len(oracle) > fuzzSlidingCapacity {
+					oracle = oracle[len(oracle)-fuzzSlidingCapacity:]
+				}
+			}
+
+			if length := s.Length(); length != len(oracle) {
+				t.Fatalf("Step %d. Expected length %d, got %d", step, len(oracle), length)
+			}
+			if err := s.checkInvariants(); err != nil {
+				t.Fatalf("Step %d. Invariant violated: %s", step, err.Error())
+			}
+		}
+	})
+}
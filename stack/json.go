@@ -0,0 +1,50 @@
+package stack
+
+import (
+	"datatypes/internal/serialize"
+	"encoding/json"
+	"errors"
+)
+
+//*************** JSON Encoding ***************
+
+//MarshalJSON encodes the stack as a JSON array, bottom of the stack first.
+//Values are collected under a read lock, so the array is a consistent view. Returns null on an uninitialized Stack.
+func (s *Stack) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+
+	s.rwMutex.RLock()
+	values := s.values()
+	s.rwMutex.RUnlock()
+
+	return json.Marshal(values)
+}
+
+//UnmarshalJSON replaces the content of the stack with the values of a JSON array, bottom of the stack first.
+//Values are decoded the way encoding/json decodes into interface{}, use UnmarshalJSONWith for typed values.
+//Returns an error on an uninitialized Stack.
+func (s *Stack) UnmarshalJSON(data []byte) error {
+	return s.UnmarshalJSONWith(data, serialize.DecodeInterface)
+}
+
+//UnmarshalJSONWith replaces the content of the stack with the values of a JSON array, bottom of the stack first.
+//Every array element is passed to decodeElement, which returns the value to store.
+//The stack is left unchanged if decoding fails. Applies the capacity like Restore.
+//Returns an error on an uninitialized Stack.
+func (s *Stack) UnmarshalJSONWith(data []byte, decodeElement func(json.RawMessage) (interface{}, error)) error {
+	if s == nil {
+		return errors.New("Stack is nil")
+	}
+
+	values, err := serialize.DecodeJSONArray(data, decodeElement)
+	if err != nil {
+		return err
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	return s.replaceValues(values)
+}
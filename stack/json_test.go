@@ -0,0 +1,118 @@
+package stack_test
+
+import (
+	. "datatypes/stack"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+//*************** JSON Encoding Test ***************
+
+func TestMarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		stackInstance *Stack
+		expectedJSON  string
+	}{
+		{stackInstance: nilStack, expectedJSON: "null"},
+		{stackInstance: emptyStack, expectedJSON: "[]"},
+		{stackInstance: oneElementStack, expectedJSON: "[0]"},
+		//Bottom of the stack goes first
+		{stackInstance: tenElementStack, expectedJSON: `["0","1","2","3","4","5","6","7","8","9"]`},
+	}
+
+	for i, aCase := range cases {
+		data, err := json.Marshal(aCase.stackInstance)
+		if err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if string(data) != aCase.expectedJSON {
+			t.Errorf("Error in case %d. Expected JSON %s, got %s", i, aCase.expectedJSON, string(data))
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	setVariablesToDefaults()
+	cases := []struct {
+		stackInstance *Stack
+		data          string
+		expectError   bool
+		//Values in pop order
+		expectedPops []interface{}
+	}{
+		{stackInstance: nilStack, data: "[]", expectError: true, expectedPops: []interface{}{}},
+		{stackInstance: emptyStack, data: `["bottom","top"]`, expectError: false, expectedPops: []interface{}{"top", "bottom"}},
+		//Existing content is replaced
+		{stackInstance: tenElementStack, data: "[true]", expectError: false, expectedPops: []interface{}{true}},
+		//Invalid JSON leaves the stack unchanged
+		{stackInstance: oneElementStack, data: "[1,", expectError: true, expectedPops: []interface{}{0}},
+	}
+
+	for i, aCase := range cases {
+		err := json.Unmarshal([]byte(aCase.data), aCase.stackInstance)
+		if aCase.expectError && err == nil {
+			t.Errorf("Error in case %d. Expected an error, got no error", i)
+		}
+		if !aCase.expectError && err != nil {
+			t.Errorf("Error in case %d. Expected no error, got %s", i, err.Error())
+		}
+		if aCase.stackInstance == nil {
+			continue
+		}
+
+		if length := aCase.stackInstance.Length(); length != len(aCase.expectedPops) {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, len(aCase.expectedPops), length)
+			continue
+		}
+		for j, expectedValue := range aCase.expectedPops {
+			value, _ := aCase.stackInstance.Pop()
+			if value != expectedValue {
+				t.Errorf("Error in case %d, pop %d. Expected value %v, got %v", i, j, expectedValue, value)
+			}
+		}
+	}
+}
+
+func TestUnmarshalJSONWith(t *testing.T) {
+	aStack := NewStack()
+	decodeInt := func(raw json.RawMessage) (interface{}, error) {
+		return strconv.Atoi(string(raw))
+	}
+
+	err := aStack.UnmarshalJSONWith([]byte("[1,2,3]"), decodeInt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	for _, expectedValue := range []int{3, 2, 1} {
+		value, _ := aStack.Pop()
+		if value != expectedValue {
+			t.Errorf("Expected typed value %d, got %v", expectedValue, value)
+		}
+	}
+
+	err = aStack.UnmarshalJSONWith([]byte("[1]"), nil)
+	if err == nil {
+		t.Errorf("Expected an error for a nil decoder, got no error")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	setVariablesToDefaults()
+	data, err := json.Marshal(tenElementStack)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+
+	decodedStack := NewStack()
+	if err := json.Unmarshal(data, decodedStack); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	for i := 9; i >= 0; i-- {
+		value, _ := decodedStack.Pop()
+		if value != strconv.Itoa(i) {
+			t.Errorf("Expected value %d, got %v", i, value)
+		}
+	}
+}
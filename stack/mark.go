@@ -0,0 +1,117 @@
+package stack
+
+import (
+	"errors"
+)
+
+//*************** Marks ***************
+
+//Mark is a token for a position in the stack, returned by Stack.Mark.
+type Mark struct {
+	id uint64
+}
+
+//Mark remembers the current top of the stack. Values pushed afterwards can be discarded with RollbackTo,
+//or kept with Commit. Marks can be nested, every mark has to be released by one of the two.
+//Panics on an uninitialized stack.
+func (s *Stack) Mark() Mark {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.nextMarkId++
+	s.marks = append(s.marks, markRecord{id: s.nextMarkId, topElement: s.topElement, length: s.length})
+	return Mark{id: s.nextMarkId}
+}
+
+//RollbackTo pops every value pushed after the mark, in one step, and releases the mark together with the marks nested in it.
+//Returns the number of values popped.
+//If the mark was already released, or the stack was popped below it, returns an error and pops nothing.
+//The mark is released in that case too.
+func (s *Stack) RollbackTo(mark Mark) (popped int, err error) {
+	if s == nil {
+		return 0, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	record, err := s.releaseMark(mark)
+	if err != nil {
+		return 0, err
+	}
+	for s.topElement != record.topElement {
+		s.popValue()
+		popped++
+	}
+	return popped, nil
+}
+
+//Commit releases the mark together with the marks nested in it, keeping the values pushed after it.
+//If the mark was already released, or the stack was popped below it, returns an error.
+//The mark is released in that case too.
+func (s *Stack) Commit(mark Mark) error {
+	if s == nil {
+		return errors.New("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	_, err := s.releaseMark(mark)
+	return err
+}
+
+//OpenMarks returns the number of marks not yet released. Returns 0 on an uninitialized Stack.
+func (s *Stack) OpenMarks() int {
+	if s == nil {
+		return 0
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return len(s.marks)
+}
+
+//*************** Marks Internal Structure ***************
+
+type markRecord struct {
+	id uint64
+	//Top element and length when the mark was taken
+	topElement *element
+	length     int
+}
+
+//releaseMark removes the mark and every newer one, then checks the marked element is still in the stack. No locking.
+func (s *Stack) releaseMark(mark Mark) (markRecord, error) {
+	position := -1
+	for i, record := range s.marks {
+		if record.id == mark.id {
+			position = i
+			break
+		}
+	}
+	if position == -1 {
+		return markRecord{}, errors.New("Mark was already released")
+	}
+	record := s.marks[position]
+	s.marks = s.marks[:position]
+
+	//The marked element has to be exactly length-record.length elements below the top.
+	//Anything else means it was popped, even if other values brought the stack back to the same depth.
+	if s.length < record.length {
+		return markRecord{}, errors.New("Stack was popped below the mark")
+	}
+	currentElement := s.topElement
+	for i := 0; i < s.length-record.length; i++ {
+		currentElement = currentElement.previousElement
+	}
+	if currentElement != record.topElement {
+		return markRecord{}, errors.New("Stack was popped below the mark")
+	}
+	return record, nil
+}
@@ -0,0 +1,122 @@
+package stack_test
+
+import (
+	. "datatypes/stack"
+	"sync"
+	"testing"
+)
+
+//*************** Marks Test ***************
+
+func TestRollbackTo(t *testing.T) {
+	s := NewStack()
+	s.Push("base")
+	mark := s.Mark()
+	s.Push(1)
+	s.Push(2)
+
+	popped, err := s.RollbackTo(mark)
+	if err != nil || popped != 2 || s.Length() != 1 {
+		t.Fatalf("Expected 2 values popped, got %d, length %d and error %v", popped, s.Length(), err)
+	}
+	if top, _ := s.Peek(); top != "base" {
+		t.Errorf("Expected base on top, got %v", top)
+	}
+	if _, err := s.RollbackTo(mark); err == nil {
+		t.Errorf("Expected an error when rolling back to a released mark")
+	}
+	if err := s.Commit(mark); err == nil {
+		t.Errorf("Expected an error when committing a released mark")
+	}
+}
+
+func TestNestedMarks(t *testing.T) {
+	s := NewStack()
+	outer := s.Mark()
+	s.Push("a")
+	inner := s.Mark()
+	s.Push("b")
+	innermost := s.Mark()
+	s.Push("c")
+
+	//Committing keeps values, rolling back the inner mark keeps the outer one
+	if err := s.Commit(innermost); err != nil || s.Length() != 3 {
+		t.Errorf("Expected commit to keep 3 values, got %d and error %v", s.Length(), err)
+	}
+	if popped, _ := s.RollbackTo(inner); popped != 2 || s.OpenMarks() != 1 {
+		t.Errorf("Expected 2 values popped and 1 open mark, got %d and %d", popped, s.OpenMarks())
+	}
+
+	inner = s.Mark()
+	s.Push("d")
+	//Rolling back the outer mark releases the inner one too
+	if popped, _ := s.RollbackTo(outer); popped != 2 || s.Length() != 0 || s.OpenMarks() != 0 {
+		t.Errorf("Expected an empty stack without marks, got %d popped, length %d, %d marks", popped, s.Length(), s.OpenMarks())
+	}
+	if err := s.Commit(inner); err == nil {
+		t.Errorf("Expected the nested mark to be stale")
+	}
+}
+
+func TestPoppedBelowMark(t *testing.T) {
+	cases := []struct {
+		popped int
+		pushed int
+	}{
+		{popped: 1, pushed: 0},
+		//Back at the same depth, but with a different value where the mark was
+		{popped: 1, pushed: 1},
+		{popped: 2, pushed: 3},
+	}
+
+	for i, aCase := range cases {
+		s := NewStack()
+		s.Push(1)
+		s.Push(2)
+		mark := s.Mark()
+		for j := 0; j < aCase.popped; j++ {
+			s.Pop()
+		}
+		for j := 0; j < aCase.pushed; j++ {
+			s.Push("new")
+		}
+		length := s.Length()
+
+		if _, err := s.RollbackTo(mark); err == nil {
+			t.Errorf("Error in case %d. Expected a stale mark", i)
+		}
+		if s.Length() != length || s.OpenMarks() != 0 {
+			t.Errorf("Error in case %d. Expected nothing popped and the mark released", i)
+		}
+	}
+}
+
+func TestMarkInvalidatedByRestore(t *testing.T) {
+	s := NewStack()
+	s.Push(1)
+	mark := s.Mark()
+	s.Restore(s.Snapshot())
+	if err := s.Commit(mark); err == nil {
+		t.Errorf("Expected Restore to release open marks")
+	}
+}
+
+func TestMarkConcurrency(t *testing.T) {
+	s := NewStack()
+	s.Push("base")
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				s.Mark()
+				s.Length()
+			}
+		}()
+	}
+	wg.Wait()
+	if s.OpenMarks() != 1600 {
+		t.Errorf("Expected 1600 open marks, got %d", s.OpenMarks())
+	}
+}
@@ -0,0 +1,98 @@
+package stack
+
+import (
+	"datatypes/internal/equality"
+	"errors"
+)
+
+//*************** Lookup Below The Top ***************
+
+//PeekAt returns the value at a depth counted from the top, 0 being the top, without removing it.
+//Expired values are skipped. If the depth is out of range or the stack is nil, returns an error.
+func (s *Stack) PeekAt(depth int) (value interface{}, err error) {
+	if s == nil {
+		return nil, errors.New("Stack is nil")
+	}
+	if depth < 0 {
+		return nil, errors.New("Depth is negative")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	found := s.find(func(index int, candidate interface{}) bool { return index == depth })
+	if found == nil {
+		return nil, errors.New("Depth is out of range")
+	}
+	return found.value, nil
+}
+
+//Bottom returns the value at the bottom of the stack, the oldest one, without removing it.
+//O(1) unless values have a TTL, then expired values at the bottom are skipped walking up.
+//If the stack is empty or nil, returns an error.
+func (s *Stack) Bottom() (value interface{}, err error) {
+	if s == nil {
+		return nil, errors.New("Stack is nil")
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	bottomElement := s.bottomElement
+	if s.ttlCount > 0 {
+		now := s.now()
+		for bottomElement != nil && bottomElement.isExpired(now) {
+			bottomElement = bottomElement.nextElement
+		}
+	}
+	if bottomElement == nil {
+		return nil, errors.New("Stack is empty")
+	}
+	return bottomElement.value, nil
+}
+
+//Search returns the depth of the topmost value equal to value, or -1 if there is none.
+//Values are compared with ==, values that can't be compared never match. Expired values are skipped.
+//Returns -1 on an uninitialized Stack.
+func (s *Stack) Search(value interface{}) int {
+	if s == nil {
+		return -1
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	depth := -1
+	s.find(func(index int, candidate interface{}) bool {
+		if equality.Equal(candidate, value) {
+			depth = index
+			return true
+		}
+		return false
+	})
+	return depth
+}
+
+//Contains reports whether a value equal to value is in the stack. See Search for how values are compared.
+func (s *Stack) Contains(value interface{}) bool {
+	return s.Search(value) >= 0
+}
+
+//*************** Lookup Internal Structure ***************
+
+//find walks the live values from top to bottom until match returns true, and returns that element.
+//Returns nil if nothing matches. No locking.
+func (s *Stack) find(match func(index int, candidate interface{}) bool) *element {
+	now := s.now()
+	index := 0
+	for currentElement := s.topElement; currentElement != nil; currentElement = currentElement.previousElement {
+		if s.ttlCount > 0 && currentElement.isExpired(now) {
+			continue
+		}
+		if match(index, currentElement.value) {
+			return currentElement
+		}
+		index++
+	}
+	return nil
+}
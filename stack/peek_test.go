@@ -0,0 +1,112 @@
+package stack_test
+
+import (
+	. "datatypes/stack"
+	"sync"
+	"testing"
+	"time"
+)
+
+//*************** Lookup Test ***************
+
+func TestPeekAtAndBottom(t *testing.T) {
+	aStack := NewStack()
+	if _, err := aStack.Bottom(); err == nil {
+		t.Errorf("Expected an error for Bottom on an empty stack")
+	}
+	for _, value := range []string{"a", "b", "c"} {
+		aStack.Push(value)
+	}
+
+	cases := []struct {
+		depth         int
+		expectedValue interface{}
+		expectError   bool
+	}{
+		{depth: 0, expectedValue: "c"},
+		{depth: 2, expectedValue: "a"},
+		{depth: 3, expectError: true},
+		{depth: -1, expectError: true},
+	}
+	for i, aCase := range cases {
+		value, err := aStack.PeekAt(aCase.depth)
+		if (err != nil) != aCase.expectError || value != aCase.expectedValue {
+			t.Errorf("Error in case %d. Expected %v, got %v with error %v", i, aCase.expectedValue, value, err)
+		}
+	}
+	if bottom, _ := aStack.Bottom(); bottom != "a" || aStack.Length() != 3 {
+		t.Errorf("Expected a at the bottom and nothing removed, got %v and length %d", bottom, aStack.Length())
+	}
+}
+
+func TestSearch(t *testing.T) {
+	aStack := NewStack()
+	aStack.Push("two")
+	aStack.Push(map[string]int{})
+	aStack.Push("two")
+	aStack.Push(1)
+
+	cases := []struct {
+		value         interface{}
+		expectedDepth int
+	}{
+		{value: 1, expectedDepth: 0},
+		{value: "two", expectedDepth: 1},
+		{value: uint(1), expectedDepth: -1},
+		{value: map[string]int{}, expectedDepth: -1},
+	}
+	for i, aCase := range cases {
+		if depth := aStack.Search(aCase.value); depth != aCase.expectedDepth {
+			t.Errorf("Error in case %d. Expected depth %d, got %d", i, aCase.expectedDepth, depth)
+		}
+		if aStack.Contains(aCase.value) != (aCase.expectedDepth >= 0) {
+			t.Errorf("Error in case %d. Contains doesn't agree with Search", i)
+		}
+	}
+}
+
+func TestLookupSkipsExpired(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aStack := NewStack()
+	aStack.SetClock(clock.Now)
+	aStack.PushWithTTL("bottom", time.Second)
+	aStack.Push("kept")
+	aStack.PushWithTTL("top", time.Second)
+	clock.Advance(2 * time.Second)
+
+	if value, _ := aStack.PeekAt(0); value != "kept" {
+		t.Errorf("Expected kept at the top, got %v", value)
+	}
+	if bottom, _ := aStack.Bottom(); bottom != "kept" {
+		t.Errorf("Expected kept at the bottom, got %v", bottom)
+	}
+	if aStack.Search("bottom") != -1 {
+		t.Errorf("Expected expired values not to be found")
+	}
+}
+
+func TestLookupConcurrency(t *testing.T) {
+	aStack := NewStack()
+	wg := sync.WaitGroup{}
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(2)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				aStack.Push(worker*1000 + i)
+			}
+		}(worker)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				aStack.PeekAt(i % 10)
+				aStack.Bottom()
+				aStack.Contains(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if aStack.Length() != 2000 || !aStack.Contains(3499) {
+		t.Errorf("Expected 2000 values including 3499")
+	}
+}
@@ -0,0 +1,88 @@
+package stack
+
+import (
+	"datatypes/binarycodec"
+	"datatypes/internal/serialize"
+	"errors"
+	"io"
+)
+
+//*************** Snapshots ***************
+
+//Snapshot is an immutable, point-in-time view of a Stack. Safe to use concurrently.
+type Snapshot struct {
+	values []interface{}
+}
+
+//Snapshot captures the current content of the stack.
+//Values are copied under a read lock, so concurrent writers are blocked only for the copy.
+//Returns an empty snapshot on an uninitialized Stack.
+func (s *Stack) Snapshot() *Snapshot {
+	if s == nil {
+		return &Snapshot{values: []interface{}{}}
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return &Snapshot{values: s.values()}
+}
+
+//Restore replaces the content of the stack with the content of the snapshot.
+//Returns an error on an uninitialized Stack or a nil snapshot. If the snapshot has more values than the capacity,
+//the bottom values are dropped in OverflowDiscardBottom mode, otherwise an *OverflowError is returned.
+func (s *Stack) Restore(snapshot *Snapshot) error {
+	if s == nil {
+		return errors.New("Stack is nil")
+	}
+	if snapshot == nil {
+		return errors.New("Snapshot is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	return s.replaceValues(snapshot.values)
+}
+
+//Load replaces the content of the stack with a snapshot read from the reader.
+//Reads everything up to EOF, the data has to be written by Snapshot.WriteTo or Snapshot.WriteFile.
+//Applies the capacity like Restore.
+func (s *Stack) Load(reader io.Reader) error {
+	if s == nil {
+		return errors.New("Stack is nil")
+	}
+
+	return serialize.Load(reader, s)
+}
+
+//Length returns the number of values in the snapshot.
+func (snapshot *Snapshot) Length() int {
+	if snapshot == nil {
+		return 0
+	}
+	return len(snapshot.values)
+}
+
+//Values returns a copy of the values in the snapshot, bottom of the stack first.
+func (snapshot *Snapshot) Values() []interface{} {
+	if snapshot == nil {
+		return []interface{}{}
+	}
+	return serialize.Copy(snapshot.values)
+}
+
+//MarshalBinary encodes the snapshot in the same format as Stack.MarshalBinary.
+func (snapshot *Snapshot) MarshalBinary() ([]byte, error) {
+	return binarycodec.Encode(binarycodec.KindStack, snapshot.Values(), binarycodec.GobCodec{})
+}
+
+//WriteTo writes the encoded snapshot to the writer. Implements io.WriterTo.
+func (snapshot *Snapshot) WriteTo(writer io.Writer) (int64, error) {
+	return serialize.WriteTo(writer, snapshot)
+}
+
+//WriteFile atomically replaces the file at path with the encoded snapshot. Suitable for checkpoint files.
+func (snapshot *Snapshot) WriteFile(path string) error {
+	return serialize.WriteFile(path, snapshot)
+}
@@ -0,0 +1,85 @@
+package stack_test
+
+import (
+	"bytes"
+	. "datatypes/stack"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+//*************** Snapshot Test ***************
+
+func TestSnapshot(t *testing.T) {
+	setVariablesToDefaults()
+	snapshot := tenElementStack.Snapshot()
+	tenElementStack.Pop()
+	tenElementStack.Push("later")
+
+	if snapshot.Length() != 10 {
+		t.Errorf("Expected length 10, got %d", snapshot.Length())
+	}
+	//Values go bottom of the stack first
+	if fmt.Sprint(snapshot.Values()) != "[0 1 2 3 4 5 6 7 8 9]" {
+		t.Errorf("Unexpected snapshot values %v", snapshot.Values())
+	}
+	if nilStack.Snapshot().Length() != 0 {
+		t.Errorf("Snapshot of a nil stack should be empty")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	setVariablesToDefaults()
+	snapshot := tenElementStack.Snapshot()
+
+	if err := emptyStack.Restore(snapshot); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := emptyStack.Pop(); value != "9" {
+		t.Errorf("Expected value 9, got %v", value)
+	}
+	if emptyStack.Length() != 9 || snapshot.Length() != 10 {
+		t.Errorf("Snapshot and restored stack share state")
+	}
+}
+
+func TestWriteToAndLoad(t *testing.T) {
+	setVariablesToDefaults()
+	buffer := bytes.Buffer{}
+	written, err := oneElementStack.Snapshot().WriteTo(&buffer)
+	if err != nil || written != int64(buffer.Len()) {
+		t.Fatalf("Expected %d bytes written without error, got %d (error: %v)", buffer.Len(), written, err)
+	}
+
+	loadedStack := NewStack()
+	if err := loadedStack.Load(&buffer); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	if value, _ := loadedStack.Peek(); value != 0 {
+		t.Errorf("Expected value 0, got %v", value)
+	}
+}
+
+//TestConcurrentSnapshot takes snapshots while other goroutines modify the stack. Run with `go test -race`.
+func TestConcurrentSnapshot(t *testing.T) {
+	aStack := NewStack()
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			aStack.Push(i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		values := aStack.Snapshot().Values()
+		for j, value := range values {
+			if value != j {
+				t.Fatalf("Snapshot is not consistent: %v", values)
+			}
+		}
+	}
+	wg.Wait()
+}
@@ -5,8 +5,10 @@
 package stack
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"time"
 )
 
 //Make runtime asserts fatal
@@ -21,6 +23,27 @@ type Stack struct {
 	length     int
 	topElement *element
 	rwMutex    sync.RWMutex
+
+	//Expiry settings, see ttl.go
+	defaultTTL   time.Duration
+	clock        func() time.Time
+	onExpire     func(value interface{})
+	ttlCount     int
+	expiredCount int
+
+	//Open marks, oldest first, see mark.go
+	marks      []markRecord
+	nextMarkId uint64
+
+	//Maximum depth, see bounded.go
+	bottomElement *element
+	capacity      int
+	overflowMode  OverflowMode
+	spaceFreed    chan struct{}
+
+	//Blocking pops, see wait.go
+	valueAdded chan struct{}
+	closed     bool
 }
 
 //NewStack initializes an empty Stack. Recommended way of initialization.
@@ -37,7 +60,8 @@ func (s *Stack) Length() int {
 	s.rwMutex.RLock()
 	defer s.rwMutex.RUnlock()
 
-	return s.lengthValue()
+	//Expired values are not counted
+	return s.liveLength()
 }
 
 //Peek returns the value at the top of the stack without removing it.
@@ -48,8 +72,59 @@ func (s *Stack) Peek() (value interface{}, err error) {
 	}
 
 	s.rwMutex.RLock()
-	defer s.rwMutex.RUnlock()
+	if !s.topIsExpired() {
+		defer s.rwMutex.RUnlock()
+		return s.peekValue()
+	}
+	s.rwMutex.RUnlock()
+
+	//Expired values at the top have to be dropped, which needs the write lock
+	s.rwMutex.Lock()
+	notifyExpired := s.dropExpiredTop()
+	value, err = s.peekValue()
+	s.rwMutex.Unlock()
+
+	notifyExpired()
+	return value, err
+}
 
+//Pop removes the value from the top of the stack. Expired values are dropped on the way.
+//If the stack is empty, returns an error.
+//Panics on an uninitialized stack.
+func (s *Stack) Pop() (value interface{}, err error) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	notifyExpired := s.dropExpiredTop()
+	value, err = s.popValue()
+	s.rwMutex.Unlock()
+
+	notifyExpired()
+	return value, err
+}
+
+//Push ads value to the top of the stack. The value expires after the default TTL, if one is set.
+//On a full stack the overflow mode decides, see SetCapacity: Push blocks until there is space,
+//discards the bottom value, or in OverflowReject mode panics with an *OverflowError.
+//Pushing to a closed stack panics, like sending on a closed channel.
+//Use TryPush or PushContext to handle a full or closed stack without panicking.
+//Panics on an uninitialized stack.
+func (s *Stack) Push(value interface{}) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	if err := s.pushBounded(context.Background(), value, 0, true, true); err != nil {
+		panic(err)
+	}
+}
+
+//*************** Stack Internal Structure ***************
+
+//Internal peek method with no locking.
+func (s *Stack) peekValue() (value interface{}, err error) {
 	length := s.lengthValue()
 	//Empty stack case
 	if length == 0 {
@@ -64,16 +139,8 @@ func (s *Stack) Peek() (value interface{}, err error) {
 	return topElement.value, nil
 }
 
-//Pop removes the value from the top of the stack. If the stack is empty, returns an error.
-//Panics on an uninitialized stack.
-func (s *Stack) Pop() (value interface{}, err error) {
-	if s == nil {
-		panic("Stack is nil")
-	}
-
-	s.rwMutex.Lock()
-	defer s.rwMutex.Unlock()
-
+//Internal pop method with no locking.
+func (s *Stack) popValue() (value interface{}, err error) {
 	length := s.lengthValue()
 	//Empty stack case
 	if length == 0 {
@@ -87,34 +154,42 @@ func (s *Stack) Pop() (value interface{}, err error) {
 	//Replace top element
 	topElement := s.topElement
 	s.topElement = topElement.previousElement
+	if s.topElement == nil {
+		s.bottomElement = nil
+	} else {
+		s.topElement.nextElement = nil
+	}
+	topElement.previousElement = nil
 	s.changeLength(-1)
+	s.clearExpiry(topElement)
+	s.signalSpaceFreed()
 	return topElement.value, nil
 }
 
-//Push ads value to the top of the stack.
-//Panics on an uninitialized stack.
-func (s *Stack) Push(value interface{}) {
-	if s == nil {
-		panic("Stack is nil")
-	}
-
-	s.rwMutex.Lock()
-	defer s.rwMutex.Unlock()
-
+//Internal push method with no locking. A zero ttl means the value never expires.
+func (s *Stack) pushValue(value interface{}, ttl time.Duration) {
 	newElement := newElement(value)
+	s.setExpiry(newElement, ttl)
 	//currentTop can be nil
 	currentTop := s.topElement
 	s.topElement = newElement
 	newElement.previousElement = currentTop
+	if currentTop == nil {
+		s.bottomElement = newElement
+	} else {
+		currentTop.nextElement = newElement
+	}
 	s.changeLength(1)
-	return
+	s.signalValueAdded()
 }
 
-//*************** Stack Internal Structure ***************
-
 type element struct {
 	value           interface{}
 	previousElement *element
+	//Element above, nil at the top. Lets the bottom be discarded in constant time.
+	nextElement *element
+	//Zero if the value never expires
+	expiresAt time.Time
 }
 
 func newElement(value interface{}) *element {
@@ -133,3 +208,84 @@ func (s *Stack) changeLength(delta int) {
 		panic("Stack has negative length")
 	}
 }
+
+//values returns all values from bottom to top. No locking.
+//Expired values are left out.
+func (s *Stack) values() []interface{} {
+	now := s.now()
+	values := make([]interface{}, 0, s.length)
+	for currentElement := s.topElement; currentElement != nil; currentElement = currentElement.previousElement {
+		if !currentElement.isExpired(now) {
+			values = append(values, currentElement.value)
+		}
+	}
+	//Collected top first, reverse to bottom first
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	return values
+}
+
+//replaceValues discards the current content and pushes values bottom to top. No locking.
+//Values over the capacity are dropped from the bottom in OverflowDiscardBottom mode,
+//in the other modes the stack is left unchanged and an *OverflowError is returned.
+func (s *Stack) replaceValues(values []interface{}) error {
+	if s.capacity > 0 && len(values) > s.capacity {
+		if s.overflowMode != OverflowDiscardBottom {
+			return &OverflowError{Capacity: s.capacity, Value: values[s.capacity]}
+		}
+		values = values[len(values)-s.capacity:]
+	}
+
+	s.topElement = nil
+	s.length = 0
+	s.bottomElement = nil
+	s.ttlCount = 0
+	s.marks = nil
+	for _, value := range values {
+		newElem := newElement(value)
+		newElem.previousElement = s.topElement
+		if s.topElement == nil {
+			s.bottomElement = newElem
+		} else {
+			s.topElement.nextElement = newElem
+		}
+		s.topElement = newElem
+		s.changeLength(1)
+	}
+	s.signalSpaceFreed()
+	s.signalValueAdded()
+	return nil
+}
+
+//checkInvariants walks the whole stack and verifies the internal structure. No locking.
+func (s *Stack) checkInvariants() error {
+	if s.length < 0 {
+		return errors.New("Length is negative")
+	}
+	if s.topElement != nil && s.topElement.nextElement != nil {
+		return errors.New("Top element points to an element above it")
+	}
+	count := 0
+	var lastElement *element
+	for currentElement := s.topElement; currentElement != nil; currentElement = currentElement.previousElement {
+		count++
+		if count > s.length {
+			return errors.New("More elements in the chain than the recorded length")
+		}
+		if currentElement.previousElement != nil && currentElement.previousElement.nextElement != currentElement {
+			return errors.New("Element below does not point back up")
+		}
+		lastElement = currentElement
+	}
+	if count != s.length {
+		return errors.New("Fewer elements in the chain than the recorded length")
+	}
+	if lastElement != s.bottomElement {
+		return errors.New("Chain does not end at the bottom element")
+	}
+	if s.capacity > 0 && s.length > s.capacity && s.overflowMode == OverflowDiscardBottom {
+		return errors.New("Stack holds more values than its capacity")
+	}
+	return nil
+}
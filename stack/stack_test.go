@@ -1,6 +1,7 @@
 package stack_test
 
 import (
+	"datatypes/linearizability"
 	. "datatypes/stack"
 	"fmt"
 	"sync"
@@ -276,3 +277,104 @@ func bombardStack(stack *Stack, wg *sync.WaitGroup) {
 	time.Sleep(time.Microsecond)
 	wg.Done()
 }
+
+//TestLinearizability records concurrent operations on a Stack and checks them against a sequential LIFO model.
+func TestLinearizability(t *testing.T) {
+	aStack := NewStack()
+	recorder := linearizability.NewRecorder()
+	var wg sync.WaitGroup
+
+	for client := 0; client < 4; client++ {
+		wg.Add(1)
+		go func(client int) {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				linearizability.RecordStack(recorder, client, aStack, linearizability.Input{Op: linearizability.Push, Value: fmt.Sprintf("%d-%d", client, i)})
+				linearizability.RecordStack(recorder, client, aStack, linearizability.Input{Op: linearizability.Peek})
+				linearizability.RecordStack(recorder, client, aStack, linearizability.Input{Op: linearizability.Pop})
+				linearizability.RecordStack(recorder, client, aStack, linearizability.Input{Op: linearizability.Length})
+			}
+		}(client)
+	}
+	wg.Wait()
+
+	if !linearizability.CheckOperations(linearizability.StackModel, recorder.History()) {
+		t.Errorf("Concurrent Stack history is not linearizable")
+	}
+}
+
+//*************** Benchmarks ***************
+
+func BenchmarkPush(b *testing.B) {
+	aStack := NewStack()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aStack.Push(i)
+	}
+}
+
+func BenchmarkPop(b *testing.B) {
+	aStack := NewStack()
+	for i := 0; i < b.N; i++ {
+		aStack.Push(i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aStack.Pop()
+	}
+}
+
+func BenchmarkPushPop(b *testing.B) {
+	aStack := NewStack()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aStack.Push(i)
+		aStack.Pop()
+	}
+}
+
+func BenchmarkPeek(b *testing.B) {
+	aStack := NewStack()
+	aStack.Push(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aStack.Peek()
+	}
+}
+
+func BenchmarkLength(b *testing.B) {
+	aStack := NewStack()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		aStack.Length()
+	}
+}
+
+func BenchmarkParallelPushPop(b *testing.B) {
+	aStack := NewStack()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			aStack.Push(0)
+			aStack.Pop()
+		}
+	})
+}
+
+func BenchmarkParallelPeek(b *testing.B) {
+	aStack := NewStack()
+	aStack.Push(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			aStack.Peek()
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package stack
+
+import (
+	"context"
+	"time"
+)
+
+//*************** Value Expiry ***************
+
+//SetDefaultTTL sets how long values added by Push stay in the stack. Zero, the default, disables expiry.
+//Values already in the stack keep their expiry time.
+//Panics on an uninitialized stack.
+func (s *Stack) SetDefaultTTL(ttl time.Duration) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.defaultTTL = ttl
+}
+
+//SetClock replaces the source of the current time used for expiry. Passing nil restores time.Now.
+//Panics on an uninitialized stack.
+func (s *Stack) SetClock(clock func() time.Time) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.clock = clock
+}
+
+//SetExpiryCallback registers a function called with every expired value the stack drops.
+//The callback runs after the stack is unlocked, so it may use the stack. Passing nil removes the callback.
+//Panics on an uninitialized stack.
+func (s *Stack) SetExpiryCallback(callback func(value interface{})) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.onExpire = callback
+}
+
+//PushWithTTL adds value to the top of the stack. The value is dropped once the ttl passes.
+//A ttl of zero or less means the value never expires. A full or closed stack is handled like in Push, including the panic.
+//Panics on an uninitialized stack.
+func (s *Stack) PushWithTTL(value interface{}, ttl time.Duration) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	if err := s.pushBounded(context.Background(), value, ttl, false, true); err != nil {
+		panic(err)
+	}
+}
+
+//ExpiredCount returns the number of expired values dropped so far.
+//Values are dropped when they reach the top of the stack. Returns 0 on an uninitialized Stack.
+func (s *Stack) ExpiredCount() int {
+	if s == nil {
+		return 0
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.expiredCount
+}
+
+//*************** Value Expiry Internal Structure ***************
+
+func (s *Stack) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock()
+}
+
+func (el *element) isExpired(now time.Time) bool {
+	return !el.expiresAt.IsZero() && !now.Before(el.expiresAt)
+}
+
+//setExpiry sets the expiry time of a new element. No locking.
+func (s *Stack) setExpiry(el *element, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	el.expiresAt = s.now().Add(ttl)
+	s.ttlCount++
+}
+
+//clearExpiry updates the number of expiring elements after an element is removed. No locking.
+func (s *Stack) clearExpiry(el *element) {
+	if el.expiresAt.IsZero() {
+		return
+	}
+	s.ttlCount--
+	if s.ttlCount < 0 && panic_on_internal_inconsistencies {
+		panic("Stack has a negative number of expiring values")
+	}
+}
+
+//topIsExpired reports whether the top value has expired. No locking.
+func (s *Stack) topIsExpired() bool {
+	if s.ttlCount == 0 || s.topElement == nil {
+		return false
+	}
+	return s.topElement.isExpired(s.now())
+}
+
+//liveLength returns the number of values that haven't expired. Walks the stack only if some values can expire. No locking.
+func (s *Stack) liveLength() int {
+	if s.ttlCount == 0 {
+		return s.lengthValue()
+	}
+
+	now := s.now()
+	length := 0
+	for currentElement := s.topElement; currentElement != nil; currentElement = currentElement.previousElement {
+		if !currentElement.isExpired(now) {
+			length++
+		}
+	}
+	return length
+}
+
+//dropExpiredTop removes expired values from the top of the stack. No locking.
+//Returns a function reporting the dropped values to the expiry callback, to be called once the stack is unlocked.
+func (s *Stack) dropExpiredTop() (notifyExpired func()) {
+	var expired []interface{}
+	for s.topIsExpired() {
+		value, err := s.popValue()
+		if err != nil {
+			if panic_on_internal_inconsistencies {
+				panic("Failed to drop an expired top value")
+			}
+			break
+		}
+		expired = append(expired, value)
+		s.expiredCount++
+	}
+
+	callback := s.onExpire
+	if callback == nil || len(expired) == 0 {
+		return noExpiredValues
+	}
+	return func() {
+		for _, value := range expired {
+			callback(value)
+		}
+	}
+}
+
+func noExpiredValues() {}
@@ -0,0 +1,91 @@
+package stack_test
+
+import (
+	. "datatypes/stack"
+	"sync"
+	"testing"
+	"time"
+)
+
+//manualClock is advanced by the tests.
+type manualClock struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *manualClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+//*************** Value Expiry Test ***************
+
+func TestPushWithTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aStack := NewStack()
+	aStack.SetClock(clock.Now)
+	expiredValues := []interface{}{}
+	aStack.SetExpiryCallback(func(value interface{}) {
+		expiredValues = append(expiredValues, value)
+	})
+
+	aStack.Push("bottom")
+	aStack.PushWithTTL("middle", time.Minute)
+	aStack.PushWithTTL("top", time.Second)
+
+	cases := []struct {
+		advance         time.Duration
+		expectedLength  int
+		expectedTop     interface{}
+		expectedExpired int
+	}{
+		{0, 3, "top", 0},
+		{time.Second, 2, "middle", 1},
+		{time.Minute, 1, "bottom", 2},
+	}
+
+	for i, aCase := range cases {
+		clock.Advance(aCase.advance)
+		if length := aStack.Length(); length != aCase.expectedLength {
+			t.Errorf("Error in case %d. Expected length %d, got %d", i, aCase.expectedLength, length)
+		}
+		if value, err := aStack.Peek(); err != nil || value != aCase.expectedTop {
+			t.Errorf("Error in case %d. Expected top value %v, got %v (error: %v)", i, aCase.expectedTop, value, err)
+		}
+		if aStack.ExpiredCount() != aCase.expectedExpired {
+			t.Errorf("Error in case %d. Expected %d expired values, got %d", i, aCase.expectedExpired, aStack.ExpiredCount())
+		}
+	}
+	if len(expiredValues) != 2 || expiredValues[0] != "top" || expiredValues[1] != "middle" {
+		t.Errorf("Unexpected values reported to the callback: %v", expiredValues)
+	}
+}
+
+func TestDefaultTTL(t *testing.T) {
+	clock := &manualClock{now: time.Unix(0, 0)}
+	aStack := NewStack()
+	aStack.SetClock(clock.Now)
+	aStack.SetDefaultTTL(time.Second)
+
+	aStack.Push("expires")
+	aStack.PushWithTTL("stays", 0)
+	aStack.Push("expires too")
+	clock.Advance(time.Second)
+
+	if value, err := aStack.Pop(); err != nil || value != "stays" {
+		t.Errorf("Expected value stays, got %v (error: %v)", value, err)
+	}
+	if _, err := aStack.Pop(); err == nil {
+		t.Errorf("Expected an error popping only expired values, got no error")
+	}
+	if aStack.Length() != 0 || aStack.ExpiredCount() != 2 {
+		t.Errorf("Expected an empty stack with 2 expired values, got length %d and %d expired", aStack.Length(), aStack.ExpiredCount())
+	}
+}
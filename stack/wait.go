@@ -0,0 +1,85 @@
+package stack
+
+import (
+	"context"
+	"errors"
+)
+
+//*************** Blocking Pop ***************
+
+//PopWait removes the value from the top of the stack, waiting until one is pushed or the context ends.
+//Returns the context error if the context ends first, an error if the stack is closed and empty.
+//Panics on an uninitialized stack.
+func (s *Stack) PopWait(ctx context.Context) (value interface{}, err error) {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	for {
+		s.rwMutex.Lock()
+		notifyExpired := s.dropExpiredTop()
+		if s.lengthValue() > 0 {
+			value, err = s.popValue()
+			s.rwMutex.Unlock()
+			notifyExpired()
+			return value, err
+		}
+		if s.closed {
+			s.rwMutex.Unlock()
+			notifyExpired()
+			return nil, errors.New("Stack is closed")
+		}
+		if s.valueAdded == nil {
+			s.valueAdded = make(chan struct{})
+		}
+		valueAdded := s.valueAdded
+		s.rwMutex.Unlock()
+		notifyExpired()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-valueAdded:
+		}
+	}
+}
+
+//Close stops the stack from accepting values and releases every waiting PopWait and blocked push.
+//Blocked PushContext calls return an error, blocked Push calls panic.
+//Values already in the stack can still be popped, PopWait returns an error once the stack is empty.
+//Safe to call more than once.
+//Panics on an uninitialized stack.
+func (s *Stack) Close() {
+	if s == nil {
+		panic("Stack is nil")
+	}
+
+	s.rwMutex.Lock()
+	defer s.rwMutex.Unlock()
+
+	s.closed = true
+	s.signalValueAdded()
+	s.signalSpaceFreed()
+}
+
+//IsClosed reports whether Close was called. Returns false on an uninitialized Stack.
+func (s *Stack) IsClosed() bool {
+	if s == nil {
+		return false
+	}
+
+	s.rwMutex.RLock()
+	defer s.rwMutex.RUnlock()
+
+	return s.closed
+}
+
+//*************** Blocking Pop Internal Structure ***************
+
+//signalValueAdded wakes up waiting pops. No locking.
+func (s *Stack) signalValueAdded() {
+	if s.valueAdded != nil {
+		close(s.valueAdded)
+		s.valueAdded = nil
+	}
+}
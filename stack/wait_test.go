@@ -0,0 +1,133 @@
+package stack_test
+
+import (
+	"context"
+	. "datatypes/stack"
+	"sync"
+	"testing"
+	"time"
+)
+
+//*************** Blocking Pop Test ***************
+
+func TestPopWait(t *testing.T) {
+	aStack := NewStack()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := aStack.PopWait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	aStack.Push("ready")
+	if value, err := aStack.PopWait(context.Background()); err != nil || value != "ready" {
+		t.Errorf("Expected ready right away, got %v with error %v", value, err)
+	}
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		aStack.Push("later")
+	}()
+	if value, err := aStack.PopWait(context.Background()); err != nil || value != "later" {
+		t.Errorf("Expected later, got %v with error %v", value, err)
+	}
+}
+
+func TestCloseReleasesWaiters(t *testing.T) {
+	aStack := NewStack()
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			_, err := aStack.PopWait(context.Background())
+			errs <- err
+		}()
+	}
+	time.Sleep(5 * time.Millisecond)
+	aStack.Close()
+	aStack.Close()
+
+	for i := 0; i < 4; i++ {
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Errorf("Expected an error after Close")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("PopWait wasn't released by Close")
+		}
+	}
+	if !aStack.IsClosed() || aStack.TryPush(1) == nil {
+		t.Errorf("Expected a closed stack to reject values")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected Push to panic on a closed stack")
+			}
+		}()
+		aStack.Push(1)
+	}()
+}
+
+func TestCloseKeepsValues(t *testing.T) {
+	aStack := NewStack()
+	aStack.Push(1)
+	aStack.Push(2)
+	aStack.Close()
+
+	for _, expected := range []int{2, 1} {
+		if value, err := aStack.PopWait(context.Background()); err != nil || value != expected {
+			t.Errorf("Expected %d, got %v with error %v", expected, value, err)
+		}
+	}
+	if _, err := aStack.PopWait(context.Background()); err == nil {
+		t.Errorf("Expected an error on a closed and empty stack")
+	}
+}
+
+func TestCloseReleasesBlockedPush(t *testing.T) {
+	aStack := NewStack()
+	aStack.SetCapacity(1, OverflowBlock)
+	aStack.Push(1)
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- aStack.PushContext(context.Background(), 2)
+	}()
+	time.Sleep(5 * time.Millisecond)
+	aStack.Close()
+	if err := <-pushed; err == nil {
+		t.Errorf("Expected the blocked push to fail after Close")
+	}
+}
+
+func TestPopWaitConsumers(t *testing.T) {
+	aStack := NewStack()
+	received := make(chan interface{}, 1000)
+	wg := sync.WaitGroup{}
+	for consumer := 0; consumer < 4; consumer++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				value, err := aStack.PopWait(context.Background())
+				if err != nil {
+					return
+				}
+				received <- value
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		aStack.Push(i)
+	}
+	for aStack.Length() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	aStack.Close()
+	wg.Wait()
+	if len(received) != 1000 {
+		t.Errorf("Expected 1000 values received, got %d", len(received))
+	}
+}
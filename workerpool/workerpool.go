@@ -0,0 +1,281 @@
+//Workerpool processes the values of a queue.Queue with a fixed number of worker goroutines.
+//Every job runs with its own context and timeout, failed jobs are retried with backoff,
+//and panics are recovered and reported as errors.
+//Safe to use concurrently.
+package workerpool
+
+import (
+	"context"
+	"datatypes/queue"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+//*************** Worker Pool Public Interface ***************
+
+//Handler processes a single value taken from the queue.
+//The context ends when the job times out or the pool is stopped.
+type Handler func(ctx context.Context, value interface{}) (output interface{}, err error)
+
+//Default values used for zero fields of Options.
+const (
+	DefaultWorkers      = 1
+	DefaultPollInterval = 10 * time.Millisecond
+)
+
+//Options configures a Pool. The zero value is valid.
+type Options struct {
+	//Number of worker goroutines.
+	Workers int
+	//Time limit of a single attempt. Zero means no limit.
+	JobTimeout time.Duration
+	//Number of retries after the first failed attempt.
+	MaxRetries int
+	//Returns the delay before a retry, attempt starts at 1. Exponential backoff from 10ms up to 1s when nil.
+	Backoff func(attempt int) time.Duration
+	//How long an idle worker waits before checking the queue again.
+	PollInterval time.Duration
+	//Capacity of the Results and Errors channels.
+	ResultBuffer int
+}
+
+//Result describes a finished job.
+type Result struct {
+	//Value taken from the queue
+	Value interface{}
+	//Output returned by the handler, nil on failure
+	Output interface{}
+	//Error of the last attempt, nil on success
+	Err error
+	//Number of attempts made
+	Attempts int
+}
+
+//Pool runs a Handler over the values of a queue. Goroutine safe.
+type Pool struct {
+	source  *queue.Queue
+	handler Handler
+	options Options
+
+	results chan Result
+	errors  chan Result
+
+	//Guards ctx and cancel, which are set by Start
+	mutex     sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	draining  chan struct{}
+	drainOnce sync.Once
+	done      chan struct{}
+}
+
+//NewPool initializes a Pool consuming from the queue. Workers are started by Start.
+func NewPool(source *queue.Queue, handler Handler, options Options) *Pool {
+	if source == nil {
+		panic("Queue is nil")
+	}
+	if handler == nil {
+		panic("Handler is nil")
+	}
+	if options.Workers <= 0 {
+		options.Workers = DefaultWorkers
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = DefaultPollInterval
+	}
+	if options.Backoff == nil {
+		options.Backoff = exponentialBackoff
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+
+	return &Pool{
+		source:   source,
+		handler:  handler,
+		options:  options,
+		results:  make(chan Result, options.ResultBuffer),
+		errors:   make(chan Result, options.ResultBuffer),
+		draining: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+//Start launches the workers. Cancelling the context stops the pool like Stop does.
+//Calling Start more than once has no effect.
+func (p *Pool) Start(ctx context.Context) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.cancel != nil {
+		return
+	}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+
+	var workers sync.WaitGroup
+	for i := 0; i < p.options.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			p.work()
+		}()
+	}
+
+	go func() {
+		defer p.finish()
+		workers.Wait()
+	}()
+}
+
+//Results returns the channel of successful jobs. Has to be read, otherwise workers block once it is full.
+//Once the pool is stopped, results that can't be sent right away are dropped. Closed after all workers exit.
+func (p *Pool) Results() <-chan Result {
+	return p.results
+}
+
+//Errors returns the channel of jobs that failed every attempt. Has to be read, otherwise workers block once it is full.
+//Once the pool is stopped, failures that can't be sent right away are dropped. Closed after all workers exit.
+func (p *Pool) Errors() <-chan Result {
+	return p.errors
+}
+
+//Shutdown lets the workers empty the queue and waits for them to exit.
+//If the context ends first, the pool is stopped and the context error returned.
+//Panics if the pool was never started.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.checkStarted()
+
+	p.drainOnce.Do(func() {
+		close(p.draining)
+	})
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		p.Stop()
+		return ctx.Err()
+	}
+}
+
+//Stop cancels running jobs and makes workers exit without taking new values. Values left in the queue stay there.
+//Panics if the pool was never started.
+func (p *Pool) Stop() {
+	p.checkStarted()
+	p.cancel()
+}
+
+//Done returns a channel closed once all workers have exited.
+func (p *Pool) Done() <-chan struct{} {
+	return p.done
+}
+
+//*************** Worker Pool Internal Structure ***************
+
+//Returned for jobs interrupted by Stop while waiting for a retry.
+var errStopped = errors.New("Pool stopped before the job succeeded")
+
+//checkStarted panics if the pool was never started.
+//Once it returns, cancel can be read without locking, Start sets it only once.
+func (p *Pool) checkStarted() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.cancel == nil {
+		panic("Pool is not started")
+	}
+}
+
+//finish closes the channels once all workers have exited.
+func (p *Pool) finish() {
+	p.cancel()
+	close(p.results)
+	close(p.errors)
+	close(p.done)
+}
+
+//Workers read ctx without locking, it is set before they are started and never changes.
+func (p *Pool) work() {
+	for {
+		if p.ctx.Err() != nil {
+			return
+		}
+
+		value, err := p.source.Dequeue()
+		if err == nil {
+			p.process(value)
+			continue
+		}
+
+		//Queue is empty
+		select {
+		case <-p.draining:
+			return
+		case <-p.ctx.Done():
+			return
+		case <-time.After(p.options.PollInterval):
+		}
+	}
+}
+
+func (p *Pool) process(value interface{}) {
+	result := Result{Value: value}
+	for {
+		result.Attempts++
+		result.Output, result.Err = p.runJob(value)
+		if result.Err == nil {
+			p.report(p.results, result)
+			return
+		}
+		if result.Attempts > p.options.MaxRetries {
+			p.report(p.errors, result)
+			return
+		}
+
+		select {
+		case <-time.After(p.options.Backoff(result.Attempts)):
+		case <-p.ctx.Done():
+			result.Output, result.Err = nil, errStopped
+			p.report(p.errors, result)
+			return
+		}
+	}
+}
+
+//report waits until the result is read. Once the pool is stopped the result is dropped instead,
+//so a consumer that stopped reading can't block the worker.
+func (p *Pool) report(channel chan Result, result Result) {
+	select {
+	case channel <- result:
+	case <-p.ctx.Done():
+	}
+}
+
+//runJob runs a single attempt, turning a panic into an error.
+func (p *Pool) runJob(value interface{}) (output interface{}, err error) {
+	jobCtx, cancel := p.ctx, context.CancelFunc(func() {})
+	if p.options.JobTimeout > 0 {
+		jobCtx, cancel = context.WithTimeout(p.ctx, p.options.JobTimeout)
+	}
+	defer cancel()
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			output = nil
+			err = fmt.Errorf("Job panicked - %v", recovered)
+		}
+	}()
+
+	return p.handler(jobCtx, value)
+}
+
+func exponentialBackoff(attempt int) time.Duration {
+	delay := 10 * time.Millisecond
+	for i := 1; i < attempt && delay < time.Second; i++ {
+		delay *= 2
+	}
+	if delay > time.Second {
+		delay = time.Second
+	}
+	return delay
+}
@@ -0,0 +1,221 @@
+package workerpool_test
+
+import (
+	"context"
+	"datatypes/queue"
+	. "datatypes/workerpool"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func noBackoff(attempt int) time.Duration {
+	return time.Microsecond
+}
+
+//collect reads both channels until they are closed.
+func collect(pool *Pool) (results []Result, failures []Result) {
+	resultsOpen, errorsOpen := true, true
+	for resultsOpen || errorsOpen {
+		select {
+		case result, ok := <-pool.Results():
+			if !ok {
+				resultsOpen = false
+				continue
+			}
+			results = append(results, result)
+		case failure, ok := <-pool.Errors():
+			if !ok {
+				errorsOpen = false
+				continue
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return results, failures
+}
+
+//*************** Public Interface Test ***************
+
+func TestProcessAndDrain(t *testing.T) {
+	aQueue := queue.NewQueue()
+	for i := 0; i < 100; i++ {
+		aQueue.Enqueue(i)
+	}
+
+	pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+		return value.(int) * 2, nil
+	}, Options{Workers: 4, PollInterval: time.Millisecond})
+	pool.Start(context.Background())
+
+	collected := make(chan []Result)
+	go func() {
+		results, _ := collect(pool)
+		collected <- results
+	}()
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %s", err.Error())
+	}
+	results := <-collected
+
+	if len(results) != 100 || aQueue.Length() != 0 {
+		t.Fatalf("Expected 100 results and an empty queue, got %d results and length %d", len(results), aQueue.Length())
+	}
+	outputs := []int{}
+	for _, result := range results {
+		outputs = append(outputs, result.Output.(int))
+	}
+	sort.Ints(outputs)
+	if outputs[0] != 0 || outputs[99] != 198 {
+		t.Errorf("Unexpected outputs %v", outputs)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	cases := []struct {
+		failures         int32
+		maxRetries       int
+		expectSuccess    bool
+		expectedAttempts int
+	}{
+		{failures: 0, maxRetries: 0, expectSuccess: true, expectedAttempts: 1},
+		{failures: 2, maxRetries: 2, expectSuccess: true, expectedAttempts: 3},
+		{failures: 3, maxRetries: 2, expectSuccess: false, expectedAttempts: 3},
+	}
+
+	for i, aCase := range cases {
+		aQueue := queue.NewQueue()
+		aQueue.Enqueue("job")
+		var calls int32
+		pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+			if atomic.AddInt32(&calls, 1) <= aCase.failures {
+				return nil, errors.New("Temporary failure")
+			}
+			return "done", nil
+		}, Options{MaxRetries: aCase.maxRetries, Backoff: noBackoff})
+		pool.Start(context.Background())
+		go pool.Shutdown(context.Background())
+
+		results, failures := collect(pool)
+		if aCase.expectSuccess && (len(results) != 1 || results[0].Attempts != aCase.expectedAttempts) {
+			t.Errorf("Error in case %d. Expected success after %d attempts, got %v", i, aCase.expectedAttempts, results)
+		}
+		if !aCase.expectSuccess && (len(failures) != 1 || failures[0].Attempts != aCase.expectedAttempts || failures[0].Err == nil) {
+			t.Errorf("Error in case %d. Expected failure after %d attempts, got %v", i, aCase.expectedAttempts, failures)
+		}
+	}
+}
+
+func TestPanicAndTimeout(t *testing.T) {
+	aQueue := queue.NewQueue()
+	aQueue.Enqueue("panic")
+	aQueue.Enqueue("slow")
+
+	pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+		if value == "panic" {
+			panic("handler bug")
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, Options{JobTimeout: 5 * time.Millisecond})
+	pool.Start(context.Background())
+	go pool.Shutdown(context.Background())
+
+	results, failures := collect(pool)
+	if len(results) != 0 || len(failures) != 2 {
+		t.Fatalf("Expected 2 failures, got %d results and %d failures", len(results), len(failures))
+	}
+	if failures[1].Err != context.DeadlineExceeded {
+		t.Errorf("Expected a timeout, got %v", failures[1].Err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	aQueue := queue.NewQueue()
+	aQueue.Enqueue("blocks until stopped")
+	aQueue.Enqueue("never taken")
+
+	pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, Options{ResultBuffer: 10})
+	pool.Start(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-pool.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Workers didn't exit after the pool was stopped")
+	}
+	if aQueue.Length() != 1 {
+		t.Errorf("Expected the untouched value to stay in the queue, got length %d", aQueue.Length())
+	}
+}
+
+func TestStopWithoutReader(t *testing.T) {
+	aQueue := queue.NewQueue()
+	for i := 0; i < 10; i++ {
+		aQueue.Enqueue(i)
+	}
+	pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+		if value.(int)%2 == 0 {
+			return nil, errors.New("Even job")
+		}
+		return value, nil
+	}, Options{Workers: 3})
+	pool.Start(context.Background())
+
+	//Nobody reads Results or Errors, so the workers block on their first job
+	time.Sleep(20 * time.Millisecond)
+	go pool.Stop()
+	pool.Stop()
+
+	select {
+	case <-pool.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Workers blocked on unread results didn't exit after Stop")
+	}
+}
+
+func TestSlowReader(t *testing.T) {
+	aQueue := queue.NewQueue()
+	for i := 0; i < 10; i++ {
+		aQueue.Enqueue(i)
+	}
+	pool := NewPool(aQueue, func(ctx context.Context, value interface{}) (interface{}, error) {
+		return value, nil
+	}, Options{Workers: 3, PollInterval: time.Millisecond})
+	pool.Start(context.Background())
+	go pool.Shutdown(context.Background())
+
+	//Workers wait for the reader while the pool runs, nothing is dropped
+	time.Sleep(20 * time.Millisecond)
+	results, _ := collect(pool)
+	if len(results) != 10 {
+		t.Errorf("Expected 10 results, got %d", len(results))
+	}
+}
+
+func Example() {
+	jobs := queue.NewQueue()
+	jobs.Enqueue("resize image")
+
+	pool := NewPool(jobs, func(ctx context.Context, value interface{}) (interface{}, error) {
+		return fmt.Sprintf("finished %v", value), nil
+	}, Options{Workers: 2, JobTimeout: time.Second, MaxRetries: 3})
+	pool.Start(context.Background())
+
+	result := <-pool.Results()
+	go pool.Shutdown(context.Background())
+	fmt.Println(result.Output)
+	//Output: finished resize image
+}
@@ -0,0 +1,183 @@
+//Workstealing is an implementation of the Chase-Lev work-stealing deque and a small fork-join scheduler built on it.
+//The owner of a deque pushes and pops at the bottom without locks, other goroutines steal from the top with a CAS.
+//Owner operations are only safe from a single goroutine, Steal and Length are safe from any goroutine.
+package workstealing
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+//*************** Deque Public Interface ***************
+
+var (
+	//ErrEmpty is returned when there is nothing to take.
+	ErrEmpty = errors.New("Deque is empty")
+	//ErrContended is returned by Steal when another goroutine took the value first. The deque may not be empty.
+	ErrContended = errors.New("Lost the race for the value, try again")
+)
+
+//Initial number of slots, grows by doubling.
+const initialCapacity = 32
+
+//Deque is a Chase-Lev work-stealing deque. PushBottom and PopBottom belong to a single owner goroutine,
+//Steal can be called by any goroutine.
+type Deque struct {
+	//Index of the oldest value, only ever increases
+	top atomic.Int64
+	//Index after the newest value, written only by the owner
+	bottom atomic.Int64
+	slots  atomic.Pointer[ring]
+}
+
+//NewDeque initializes an empty Deque. Recommended way of initialization.
+func NewDeque() *Deque {
+	d := &Deque{}
+	d.slots.Store(newRing(initialCapacity))
+	return d
+}
+
+//Length returns the number of values in the deque. Only a snapshot while other goroutines use it.
+//Returns 0 on an uninitialized Deque.
+func (d *Deque) Length() int {
+	if d == nil {
+		return 0
+	}
+	length := d.bottom.Load() - d.top.Load()
+	if length < 0 {
+		return 0
+	}
+	return int(length)
+}
+
+//PushBottom adds value at the bottom. Owner only.
+//Panics on an uninitialized deque.
+func (d *Deque) PushBottom(value interface{}) {
+	if d == nil {
+		panic("Deque is nil")
+	}
+
+	bottom := d.bottom.Load()
+	top := d.top.Load()
+	slots := d.slots.Load()
+	if bottom-top >= slots.capacity() {
+		slots = slots.grow(top, bottom)
+		d.slots.Store(slots)
+	}
+	slots.put(bottom, value)
+	d.bottom.Store(bottom + 1)
+}
+
+//PopBottom removes the newest value. Owner only.
+//If the deque is empty, returns ErrEmpty.
+//Panics on an uninitialized deque.
+func (d *Deque) PopBottom() (valueRemoved interface{}, err error) {
+	if d == nil {
+		panic("Deque is nil")
+	}
+
+	//Claim the bottom slot first, thieves that see the new bottom stay away from it
+	bottom := d.bottom.Load() - 1
+	slots := d.slots.Load()
+	d.bottom.Store(bottom)
+	top := d.top.Load()
+
+	if top > bottom {
+		d.bottom.Store(bottom + 1)
+		return nil, ErrEmpty
+	}
+
+	valueRemoved, taken := slots.get(bottom)
+	if top == bottom {
+		//Last value - race the thieves for it
+		if !d.top.CompareAndSwap(top, top+1) {
+			valueRemoved, err = nil, ErrEmpty
+		}
+		d.bottom.Store(bottom + 1)
+	}
+	if err == nil {
+		slots.clear(bottom, taken)
+	}
+	return valueRemoved, err
+}
+
+//Steal removes the oldest value. Safe from any goroutine.
+//If the deque is empty, returns ErrEmpty. If another goroutine took the value first, returns ErrContended.
+//Panics on an uninitialized deque.
+func (d *Deque) Steal() (valueRemoved interface{}, err error) {
+	if d == nil {
+		panic("Deque is nil")
+	}
+
+	top := d.top.Load()
+	bottom := d.bottom.Load()
+	if top >= bottom {
+		return nil, ErrEmpty
+	}
+
+	slots := d.slots.Load()
+	valueRemoved, taken := slots.get(top)
+	if !d.top.CompareAndSwap(top, top+1) {
+		return nil, ErrContended
+	}
+	//The owner may have grown the ring in the meantime, the grown ring holds the slot too
+	slots.clear(top, taken)
+	if current := d.slots.Load(); current != slots {
+		current.clear(top, taken)
+	}
+	return valueRemoved, nil
+}
+
+//*************** Deque Internal Structure ***************
+
+//ring is a circular array of slots indexed by the ever increasing top and bottom indexes.
+//Slots hold pointers so thieves can read them atomically while the owner writes.
+type ring struct {
+	slots []atomic.Pointer[slot]
+	mask  int64
+}
+
+type slot struct {
+	value interface{}
+}
+
+//newRing returns a ring with capacity slots, capacity has to be a power of two.
+func newRing(capacity int64) *ring {
+	return &ring{slots: make([]atomic.Pointer[slot], capacity), mask: capacity - 1}
+}
+
+func (r *ring) capacity() int64 {
+	return int64(len(r.slots))
+}
+
+//get returns the value at index and the slot holding it. A thief holding a stale top can find an empty slot,
+//in a grown ring or one cleared after a take, its CAS fails afterwards, so the nil is never handed out.
+func (r *ring) get(index int64) (interface{}, *slot) {
+	stored := r.slots[index&r.mask].Load()
+	if stored == nil {
+		return nil, nil
+	}
+	return stored.value, stored
+}
+
+//clear empties the slot at index if it still holds taken, so a taken value doesn't stay reachable.
+//A slot already reused by a later push is left alone.
+func (r *ring) clear(index int64, taken *slot) {
+	if taken != nil {
+		r.slots[index&r.mask].CompareAndSwap(taken, nil)
+	}
+}
+
+func (r *ring) put(index int64, value interface{}) {
+	r.slots[index&r.mask].Store(&slot{value: value})
+}
+
+//grow returns a ring twice the size holding the values between top and bottom.
+//The old ring stays valid for thieves still reading from it.
+func (r *ring) grow(top int64, bottom int64) *ring {
+	grown := newRing(2 * r.capacity())
+	for index := top; index < bottom; index++ {
+		grown.slots[index&grown.mask].Store(r.slots[index&r.mask].Load())
+	}
+	return grown
+}
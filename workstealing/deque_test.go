@@ -0,0 +1,185 @@
+package workstealing
+
+import (
+	"datatypes/linearizability"
+	"runtime"
+	"sync"
+	"testing"
+	"weak"
+)
+
+//*************** Deque Test ***************
+
+func TestOwnerAndThiefOrder(t *testing.T) {
+	d := NewDeque()
+	if _, err := d.PopBottom(); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+	if _, err := d.Steal(); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		d.PushBottom(i)
+	}
+	if value, _ := d.PopBottom(); value != 4 {
+		t.Errorf("Expected owner to pop the newest value 4, got %v", value)
+	}
+	if value, _ := d.Steal(); value != 0 {
+		t.Errorf("Expected thief to steal the oldest value 0, got %v", value)
+	}
+	if d.Length() != 3 {
+		t.Errorf("Expected length 3, got %d", d.Length())
+	}
+}
+
+func TestTakenValuesAreReleased(t *testing.T) {
+	type task struct {
+		id   int
+		data [64]byte
+	}
+	d := NewDeque()
+	taken := []weak.Pointer[task]{}
+	for i := 0; i < 5; i++ {
+		value := &task{id: i}
+		if i != 2 {
+			taken = append(taken, weak.Make(value))
+		}
+		d.PushBottom(value)
+	}
+
+	//Steals take 0 and 1, pops take 4 and 3, task 2 stays in the deque
+	d.Steal()
+	d.Steal()
+	d.PopBottom()
+	d.PopBottom()
+	runtime.GC()
+
+	for i, pointer := range taken {
+		if pointer.Value() != nil {
+			t.Errorf("Error in case %d. Expected the taken task to be collected", i)
+		}
+	}
+	if value, _ := d.PopBottom(); value.(*task).id != 2 {
+		t.Errorf("Expected the remaining task 2, got %v", value)
+	}
+}
+
+func TestGrowth(t *testing.T) {
+	d := NewDeque()
+	const count = initialCapacity*4 + 3
+
+	//Move top forward first so the values wrap around the ring
+	for i := 0; i < 10; i++ {
+		d.PushBottom(-1)
+		d.Steal()
+	}
+	for i := 0; i < count; i++ {
+		d.PushBottom(i)
+	}
+	if d.Length() != count || d.slots.Load().capacity() < count {
+		t.Fatalf("Expected %d values in a grown ring, got length %d and capacity %d", count, d.Length(), d.slots.Load().capacity())
+	}
+	for i := 0; i < count; i++ {
+		if value, err := d.Steal(); err != nil || value != i {
+			t.Fatalf("Expected %d, got %v with error %v", i, value, err)
+		}
+	}
+}
+
+func TestConcurrentStealing(t *testing.T) {
+	const values = 20000
+	d := NewDeque()
+	seen := make([]int32, values)
+	var seenMutex sync.Mutex
+	record := func(value interface{}) {
+		seenMutex.Lock()
+		seen[value.(int)]++
+		seenMutex.Unlock()
+	}
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	for thief := 0; thief < 4; thief++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				value, err := d.Steal()
+				if err == nil {
+					record(value)
+					continue
+				}
+				select {
+				case <-done:
+					if d.Length() == 0 {
+						return
+					}
+				default:
+				}
+			}
+		}()
+	}
+
+	//The owner pushes everything and pops some of it back
+	for i := 0; i < values; i++ {
+		d.PushBottom(i)
+		if i%3 == 0 {
+			if value, err := d.PopBottom(); err == nil {
+				record(value)
+			}
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	for value, count := range seen {
+		if count != 1 {
+			t.Fatalf("Value %d was taken %d times", value, count)
+		}
+	}
+}
+
+//retryingDeque retries steals that lost a race, so a failed steal means the deque was empty.
+type retryingDeque struct {
+	*Deque
+}
+
+func (d retryingDeque) Steal() (interface{}, error) {
+	for {
+		value, err := d.Deque.Steal()
+		if err != ErrContended {
+			return value, err
+		}
+	}
+}
+
+//TestLinearizability records the owner and thieves working on one Deque and checks them against a sequential model.
+func TestLinearizability(t *testing.T) {
+	d := retryingDeque{NewDeque()}
+	recorder := linearizability.NewRecorder()
+	var wg sync.WaitGroup
+
+	for thief := 1; thief <= 3; thief++ {
+		wg.Add(1)
+		go func(thief int) {
+			defer wg.Done()
+			for i := 0; i < 30; i++ {
+				linearizability.RecordDeque(recorder, thief, d, linearizability.Input{Op: linearizability.Steal})
+			}
+		}(thief)
+	}
+
+	//Owner operations are only safe from a single goroutine
+	for i := 0; i < 60; i++ {
+		linearizability.RecordDeque(recorder, 0, d, linearizability.Input{Op: linearizability.PushBottom, Value: i})
+		if i%3 == 0 {
+			linearizability.RecordDeque(recorder, 0, d, linearizability.Input{Op: linearizability.PopBottom})
+		}
+	}
+	wg.Wait()
+
+	if !linearizability.CheckOperations(linearizability.DequeModel, recorder.History()) {
+		t.Errorf("Concurrent Deque history is not linearizable")
+	}
+}
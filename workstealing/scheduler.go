@@ -0,0 +1,206 @@
+package workstealing
+
+import (
+	"datatypes/queue"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+//*************** Scheduler Public Interface ***************
+
+//Task is a unit of work. The worker running it can be used to spawn subtasks.
+type Task func(worker *Worker)
+
+//Scheduler runs tasks on a fixed set of workers, each with its own Deque.
+//Tasks submitted from outside go through a shared queue.Queue, subtasks are pushed to the spawning worker's deque,
+//and idle workers steal from the others. Workers with nothing to do sleep until a task is added. Goroutine safe.
+//
+//A panicking task doesn't stop its worker. The first panic is raised again by Wait or Close.
+type Scheduler struct {
+	workers  []*Worker
+	injected *queue.Queue
+	//Tasks submitted or spawned, but not finished
+	pending sync.WaitGroup
+	stopped atomic.Bool
+	exited  sync.WaitGroup
+	steals  atomic.Int64
+
+	//Idle workers wait on wakeup. Every added task bumps taskAdded, so a worker can tell it missed one.
+	idleMutex sync.Mutex
+	wakeup    *sync.Cond
+	taskAdded atomic.Uint64
+	idle      atomic.Int32
+
+	panicMutex sync.Mutex
+	//First value a task panicked with and not yet raised
+	panicked interface{}
+}
+
+//Worker runs tasks on one goroutine of a Scheduler.
+type Worker struct {
+	id        int
+	scheduler *Scheduler
+	deque     *Deque
+	random    *rand.Rand
+}
+
+//NewScheduler starts a Scheduler with the given number of workers. Values below 1 mean runtime.GOMAXPROCS(0).
+func NewScheduler(workers int) *Scheduler {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	s := &Scheduler{injected: queue.NewQueue()}
+	s.wakeup = sync.NewCond(&s.idleMutex)
+	for id := 0; id < workers; id++ {
+		s.workers = append(s.workers, &Worker{id: id, scheduler: s, deque: NewDeque(), random: rand.New(rand.NewSource(int64(id) + 1))})
+	}
+	s.exited.Add(workers)
+	for _, worker := range s.workers {
+		go worker.run()
+	}
+	return s
+}
+
+//Submit adds a task from outside the scheduler.
+//Panics if the scheduler was closed.
+func (s *Scheduler) Submit(task Task) {
+	if s.stopped.Load() {
+		panic("Scheduler is closed")
+	}
+	s.pending.Add(1)
+	s.injected.Enqueue(task)
+	s.notify()
+}
+
+//Wait blocks until every submitted task, and every subtask they spawned, has finished.
+//If a task panicked since the last Wait, panics with the same value.
+func (s *Scheduler) Wait() {
+	s.pending.Wait()
+	s.raisePanic()
+}
+
+//Close waits for all tasks to finish and stops the workers. Safe to call more than once.
+//Like Wait, panics if a task panicked, after the workers have stopped.
+func (s *Scheduler) Close() {
+	s.pending.Wait()
+	s.stopped.Store(true)
+	s.idleMutex.Lock()
+	s.wakeup.Broadcast()
+	s.idleMutex.Unlock()
+	s.exited.Wait()
+	s.raisePanic()
+}
+
+//Steals returns the number of tasks taken from another worker's deque so far.
+func (s *Scheduler) Steals() int64 {
+	return s.steals.Load()
+}
+
+//ID returns the index of the worker, from 0 to the number of workers minus one.
+func (w *Worker) ID() int {
+	return w.id
+}
+
+//Spawn adds a subtask to the worker's own deque. Only call it from a task running on this worker.
+func (w *Worker) Spawn(task Task) {
+	w.scheduler.pending.Add(1)
+	w.deque.PushBottom(task)
+	w.scheduler.notify()
+}
+
+//*************** Scheduler Internal Structure ***************
+
+func (w *Worker) run() {
+	defer w.scheduler.exited.Done()
+
+	s := w.scheduler
+	for !s.stopped.Load() {
+		//Read before looking, so a task added after the look is noticed before sleeping
+		s.idle.Add(1)
+		added := s.taskAdded.Load()
+		task := w.findTask()
+		if task == nil {
+			s.idleMutex.Lock()
+			for s.taskAdded.Load() == added && !s.stopped.Load() {
+				s.wakeup.Wait()
+			}
+			s.idleMutex.Unlock()
+			s.idle.Add(-1)
+			continue
+		}
+		s.idle.Add(-1)
+		w.runTask(task)
+	}
+}
+
+//runTask runs a task, recording a panic instead of letting it stop the worker.
+func (w *Worker) runTask(task Task) {
+	defer w.scheduler.pending.Done()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			w.scheduler.panicMutex.Lock()
+			if w.scheduler.panicked == nil {
+				w.scheduler.panicked = recovered
+			}
+			w.scheduler.panicMutex.Unlock()
+		}
+	}()
+	task(w)
+}
+
+//notify wakes an idle worker after a task was added.
+func (s *Scheduler) notify() {
+	s.taskAdded.Add(1)
+	if s.idle.Load() == 0 {
+		return
+	}
+	//Taking the lock makes sure a worker that saw the old taskAdded is already waiting
+	s.idleMutex.Lock()
+	s.idleMutex.Unlock()
+	s.wakeup.Signal()
+}
+
+//raisePanic panics with the recorded task panic, if any, and forgets it.
+func (s *Scheduler) raisePanic() {
+	s.panicMutex.Lock()
+	recovered := s.panicked
+	s.panicked = nil
+	s.panicMutex.Unlock()
+	if recovered != nil {
+		panic(recovered)
+	}
+}
+
+//findTask looks at the worker's own deque, then the shared queue, then the other workers' deques.
+func (w *Worker) findTask() Task {
+	if value, err := w.deque.PopBottom(); err == nil {
+		return value.(Task)
+	}
+	if value, err := w.scheduler.injected.Dequeue(); err == nil {
+		return value.(Task)
+	}
+
+	workers := w.scheduler.workers
+	start := w.random.Intn(len(workers))
+	for i := 0; i < len(workers); i++ {
+		victim := workers[(start+i)%len(workers)]
+		if victim == w {
+			continue
+		}
+		for {
+			value, err := victim.deque.Steal()
+			if err == ErrContended {
+				continue
+			}
+			if err == nil {
+				w.scheduler.steals.Add(1)
+				return value.(Task)
+			}
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package workstealing_test
+
+import (
+	. "datatypes/workstealing"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+//sum adds the numbers from low to high-1 by splitting the range into subtasks.
+func sum(worker *Worker, low int, high int, total *int64) {
+	if high-low <= 16 {
+		partial := 0
+		for i := low; i < high; i++ {
+			partial += i
+		}
+		atomic.AddInt64(total, int64(partial))
+		return
+	}
+	middle := (low + high) / 2
+	worker.Spawn(func(w *Worker) { sum(w, low, middle, total) })
+	sum(worker, middle, high, total)
+}
+
+//*************** Scheduler Test ***************
+
+func TestForkJoin(t *testing.T) {
+	scheduler := NewScheduler(4)
+	defer scheduler.Close()
+
+	for round := 0; round < 3; round++ {
+		var total int64
+		scheduler.Submit(func(w *Worker) { sum(w, 0, 100000, &total) })
+		scheduler.Wait()
+		if total != 4999950000 {
+			t.Fatalf("Error in round %d. Expected 4999950000, got %d", round, total)
+		}
+	}
+}
+
+func TestManySubmissions(t *testing.T) {
+	scheduler := NewScheduler(0)
+	var count int64
+	for i := 0; i < 1000; i++ {
+		scheduler.Submit(func(w *Worker) { atomic.AddInt64(&count, 1) })
+	}
+	scheduler.Close()
+
+	if count != 1000 {
+		t.Errorf("Expected 1000 tasks to run, got %d", count)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected Submit to panic after Close")
+		}
+	}()
+	scheduler.Submit(func(w *Worker) {})
+}
+
+func TestTaskPanic(t *testing.T) {
+	scheduler := NewScheduler(2)
+	var count int64
+	for i := 0; i < 100; i++ {
+		scheduler.Submit(func(w *Worker) {
+			atomic.AddInt64(&count, 1)
+			if i%10 == 0 {
+				panic("task bug")
+			}
+		})
+	}
+
+	waitPanic := func() (recovered interface{}) {
+		defer func() { recovered = recover() }()
+		scheduler.Wait()
+		return nil
+	}
+	if recovered := waitPanic(); recovered != "task bug" {
+		t.Errorf("Expected Wait to panic with the task's value, got %v", recovered)
+	}
+	if count != 100 {
+		t.Errorf("Expected all 100 tasks to run, got %d", count)
+	}
+
+	//The workers keep running, and the panic is only raised once
+	scheduler.Submit(func(w *Worker) { atomic.AddInt64(&count, 1) })
+	scheduler.Close()
+	if count != 101 {
+		t.Errorf("Expected 101 tasks to run, got %d", count)
+	}
+}
+
+func ExampleScheduler() {
+	scheduler := NewScheduler(4)
+	var total int64
+	scheduler.Submit(func(w *Worker) { sum(w, 1, 101, &total) })
+	scheduler.Close()
+
+	fmt.Println(total)
+	//Output: 5050
+}